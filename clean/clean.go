@@ -6,9 +6,6 @@ import (
 	"GoRelCli/utils/schema_parser"
 	"GoRelCli/utils/validator"
 	"errors"
-	"gopkg.in/yaml.v3"
-	"os"
-	"path/filepath"
 )
 
 func checkFlags(args ...string) (valid bool) {
@@ -18,31 +15,6 @@ func checkFlags(args ...string) (valid bool) {
 	return true
 }
 
-func writeYmlFS(schema schema_model.GoRelSchema, path string) (err error) {
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		return err
-	}
-
-	file, err := os.OpenFile(absPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0777)
-	if err != nil {
-		return err
-	}
-
-	defer func(file *os.File) {
-		if errInn := file.Close(); errInn != nil {
-			err = errInn
-		}
-	}(file)
-
-	encoder := yaml.NewEncoder(file)
-	if err := encoder.Encode(schema); err != nil {
-		return err
-	}
-
-	return err
-}
-
 func Clean(args ...string) error {
 	if !checkFlags(args...) {
 		return errors.New("path flag should be provided")
@@ -76,7 +48,7 @@ func Clean(args ...string) error {
 	}
 
 	if err := logger.LogStep("write schema to fs", func() error {
-		if err := writeYmlFS(goRelSchema, path); err != nil {
+		if err := schema_parser.WriteYmlSchema(path, goRelSchema); err != nil {
 			return err
 		}
 		return nil
@@ -0,0 +1,227 @@
+package generate
+
+import (
+	"GoRelCli/models/schema_model"
+	"GoRelCli/utils/naming"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// GoRelCustomFileImpl is a file rendered from the user template defined in the generator block of the schema
+type GoRelCustomFileImpl struct {
+	absolutePath string
+	header       string
+	content      string
+}
+
+// customTemplateData is passed to user templates. Model is set for templates with model scope and Enum for templates with enum scope.
+type customTemplateData struct {
+	// Module is import path of the project folder, generated packages are imported as Module + "/gorel/models"
+	Module   string
+	Provider schema_model.Provider
+	Models   []customModelData
+	Enums    []enumTemplateData
+	Model    customModelData
+	Enum     enumTemplateData
+}
+
+type customModelData struct {
+	Name string
+	// Imports are required by GoType of the properties
+	Imports    []string
+	Properties []customPropertyData
+	Relations  []clientRelationTemplateData
+}
+
+type customPropertyData struct {
+	// Name and Type are written as in the schema
+	Name string
+	Type string
+	// Field, GoType and Tag are used by the struct of the model, GoType is written as in gorel/models package (e.g. *enums.UserRole, []Todo)
+	Field  string
+	GoType string
+	Tag    string
+	// SqlType is type of the column for the provider, it is empty for relations
+	SqlType        string
+	Default        string
+	RelationName   string
+	RelationField  string
+	ReferenceField string
+	IsId           bool
+	IsUnique       bool
+	IsNullable     bool
+	IsArray        bool
+	IsEnum         bool
+	IsRelation     bool
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"upperFirst": naming.UpperFirst,
+	"lowerFirst": naming.LowerFirst,
+	"pluralize":  naming.Pluralize,
+	"goName":     naming.GoName,
+	"camel":      naming.Camel,
+	"snake":      naming.Snake,
+	"join":       strings.Join,
+}
+
+func (g *GoRelCustomFileImpl) Create(object ObjectUnionType, schema schema_model.GoRelSchema, enumNames []string, modelNames []string, modulePath string, projectPath string) error {
+	content, err := os.ReadFile(object.template.Path)
+	if err != nil {
+		return errors.New(fmt.Sprintf("can't read template %s: %s", object.template.Path, err))
+	}
+	contentTemplate, err := template.New(filepath.Base(object.template.Path)).Funcs(customTemplateFuncs).Parse(string(content))
+	if err != nil {
+		return errors.New(fmt.Sprintf("can't parse template %s: %s", object.template.Path, err))
+	}
+	outputTemplate, err := template.New("output").Funcs(customTemplateFuncs).Parse(object.template.Output)
+	if err != nil {
+		return errors.New(fmt.Sprintf("can't parse output %s of template %s: %s", object.template.Output, object.template.Path, err))
+	}
+
+	data, err := createCustomTemplateData(schema, enumNames, modelNames, modulePath)
+	if err != nil {
+		return err
+	}
+	for _, model := range data.Models {
+		if model.Name == object.model.Name {
+			data.Model = model
+		}
+	}
+	for _, enum := range data.Enums {
+		if enum.Name == object.enum.Name {
+			data.Enum = enum
+		}
+	}
+
+	var output bytes.Buffer
+	if err := outputTemplate.Execute(&output, data); err != nil {
+		return errors.New(fmt.Sprintf("can't execute output %s of template %s: %s", object.template.Output, object.template.Path, err))
+	}
+	g.absolutePath = filepath.Join(projectPath, filepath.FromSlash(output.String()))
+
+	var buffer bytes.Buffer
+	if err := contentTemplate.Execute(&buffer, data); err != nil {
+		return errors.New(fmt.Sprintf("can't execute template %s for %s: %s", object.template.Path, output.String(), err))
+	}
+	if filepath.Ext(g.absolutePath) != ".go" {
+		g.content = buffer.String()
+		return nil
+	}
+	g.content, err = formatSource(output.String(), buffer.Bytes())
+	return err
+}
+
+// createCustomTemplateData collects models and enums of the schema with types resolved the same way as for generated structs
+func createCustomTemplateData(schema schema_model.GoRelSchema, enumNames []string, modelNames []string, modulePath string) (customTemplateData, error) {
+	data := customTemplateData{Module: modulePath, Provider: schema.Connection.Provider}
+	generatedFile := GoRelGeneratedFileImpl{}
+
+	for _, model := range schema.Models {
+		structData, err := generatedFile.generateStructModel(model, enumNames, modelNames, modulePath, schema.Generator)
+		if err != nil {
+			return customTemplateData{}, err
+		}
+
+		modelData := customModelData{
+			Name:      model.Name,
+			Imports:   structData.Imports,
+			Relations: clientRelations(model, schema),
+		}
+		// every property becomes a field of the struct, so fields have the same order as properties
+		for index, property := range model.Properties {
+			baseType := strings.TrimSuffix(strings.TrimSuffix(property.Type, "?"), "[]")
+			modelData.Properties = append(modelData.Properties, customPropertyData{
+				Name:           property.Name,
+				Type:           property.Type,
+				Field:          structData.Fields[index].Name,
+				GoType:         structData.Fields[index].Type,
+				Tag:            structData.Fields[index].Tag,
+				SqlType:        customSqlType(property, schema),
+				Default:        property.Default,
+				RelationName:   property.RelationName,
+				RelationField:  property.RelationField,
+				ReferenceField: property.ReferenceField,
+				IsId:           model.IsPrimaryKey(property.Name),
+				IsUnique:       property.Unique,
+				IsNullable:     strings.HasSuffix(property.Type, "?"),
+				IsArray:        strings.HasSuffix(property.Type, "[]"),
+				IsEnum:         slices.Contains(enumNames, baseType),
+				IsRelation:     slices.Contains(modelNames, baseType),
+			})
+		}
+		data.Models = append(data.Models, modelData)
+	}
+
+	for _, enum := range schema.Enums {
+		data.Enums = append(data.Enums, generatedFile.generateEnum(enum, schema.Generator))
+	}
+	return data, nil
+}
+
+// customSqlType returns column type of the property for the provider of the schema, relations have no column type
+func customSqlType(property schema_model.Property, schema schema_model.GoRelSchema) string {
+	enumName := strings.TrimSuffix(property.Type, "?")
+	for _, enum := range schema.Enums {
+		if enum.Name != enumName {
+			continue
+		}
+		switch schema.Connection.Provider {
+		case schema_model.MySQL:
+			return fmt.Sprintf("enum('%s')", strings.Join(enum.Values, "','"))
+		case schema_model.SQLite:
+			return "TEXT"
+		default:
+			return enum.Name
+		}
+	}
+
+	var sqlType string
+	var isValid bool
+	switch schema.Connection.Provider {
+	case schema_model.MySQL:
+		sqlType, isValid = property.GetMySqlType()
+	case schema_model.SQLite:
+		sqlType, isValid = property.GetSqliteType()
+	default:
+		sqlType, isValid = property.GetPostgresType()
+	}
+	if !isValid {
+		return ""
+	}
+	return strings.TrimSuffix(sqlType, " NOT NULL")
+}
+
+func (g *GoRelCustomFileImpl) Path() string {
+	return g.absolutePath
+}
+
+func (g *GoRelCustomFileImpl) SetHeader(header string) {
+	g.header = header
+}
+
+func (g *GoRelCustomFileImpl) WriteFS() error {
+	if err := os.MkdirAll(filepath.Dir(g.absolutePath), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(g.absolutePath, []byte(g.header+g.content), 0777)
+}
+
+func (g *GoRelCustomFileImpl) WriteFSAsync(c chan error, syncGroup *sync.WaitGroup) {
+	defer syncGroup.Done()
+
+	if err := g.WriteFS(); err != nil {
+		c <- err
+	}
+}
+
+func (g *GoRelCustomFileImpl) Log() {
+	fmt.Println(fmt.Sprintf("Contents of custom file with path %s:\n%s", g.absolutePath, g.content))
+}
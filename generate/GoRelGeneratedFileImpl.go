@@ -2,10 +2,9 @@ package generate
 
 import (
 	"GoRelCli/models/schema_model"
+	"GoRelCli/utils/naming"
 	"errors"
 	"fmt"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 	"os"
 	"path/filepath"
 	"slices"
@@ -15,6 +14,7 @@ import (
 
 type GoRelGeneratedFileImpl struct {
 	absolutePath string
+	header       string
 	content      string
 	fileType     FileType
 }
@@ -24,34 +24,56 @@ type FileType string
 type ObjectUnionType struct {
 	model    schema_model.Model
 	enum     schema_model.Enum
+	template schema_model.Template
 	fileType FileType
 }
 
 const (
-	MODEL FileType = "Models"
-	ENUM           = "Enums"
+	MODEL          FileType = "Models"
+	ENUM                    = "Enums"
+	OPTIONAL                = "Optional"
+	CLIENT                  = "Client"
+	CLIENT_MODEL            = "Client model"
+	CLIENT_FILTERS          = "Client filters"
 )
 
-func (g *GoRelGeneratedFileImpl) Create(object ObjectUnionType, enumNames []string, modelNames []string, projectName string, projectPath string) error {
+func (g *GoRelGeneratedFileImpl) Create(object ObjectUnionType, schema schema_model.GoRelSchema, enumNames []string, modelNames []string, modulePath string, projectPath string) error {
 	g.fileType = object.fileType
-	if err := g.generateFileContent(object, enumNames, modelNames, projectName); err != nil {
+	if err := g.generateFileContent(object, schema, enumNames, modelNames, modulePath); err != nil {
 		return err
 	}
 
-	relativePath, err := filepath.Abs(projectPath)
+	absolutePath, err := filepath.Abs(projectPath)
 	if err != nil {
 		return err
 	}
 
-	if g.fileType == MODEL {
-		g.absolutePath = fmt.Sprintf("%s\\gorel\\models\\%s.go", relativePath, object.model.Name)
-	} else {
-		g.absolutePath = fmt.Sprintf("%s\\gorel\\enums\\%s.go", relativePath, object.enum.Name)
+	switch g.fileType {
+	case MODEL:
+		g.absolutePath = filepath.Join(absolutePath, "gorel", "models", fmt.Sprintf("%s.go", object.model.Name))
+	case OPTIONAL:
+		g.absolutePath = filepath.Join(absolutePath, "gorel", "models", "Optional.go")
+	case CLIENT:
+		g.absolutePath = filepath.Join(absolutePath, "gorel", "client", "client.go")
+	case CLIENT_MODEL:
+		g.absolutePath = filepath.Join(absolutePath, "gorel", "client", fmt.Sprintf("%s.go", object.model.Name))
+	case CLIENT_FILTERS:
+		g.absolutePath = filepath.Join(absolutePath, "gorel", "client", "filters.go")
+	default:
+		g.absolutePath = filepath.Join(absolutePath, "gorel", "enums", fmt.Sprintf("%s.go", object.enum.Name))
 	}
 
 	return nil
 }
 
+func (g *GoRelGeneratedFileImpl) Path() string {
+	return g.absolutePath
+}
+
+func (g *GoRelGeneratedFileImpl) SetHeader(header string) {
+	g.header = header
+}
+
 func (g *GoRelGeneratedFileImpl) WriteFS() (err error) {
 	file, err := os.OpenFile(g.absolutePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0777)
 	if err != nil {
@@ -64,7 +86,7 @@ func (g *GoRelGeneratedFileImpl) WriteFS() (err error) {
 		}
 	}(file)
 
-	byteRepr := []byte(g.content)
+	byteRepr := []byte(g.header + g.content)
 	n, err := file.Write(byteRepr)
 	if err != nil {
 		return err
@@ -93,92 +115,316 @@ func (g *GoRelGeneratedFileImpl) writeFile(file *os.File, text string) error {
 	return nil
 }
 
-func (g *GoRelGeneratedFileImpl) generateFileContent(object ObjectUnionType, enumNames []string, modelNames []string, projectName string) error {
-	var structString string
+func (g *GoRelGeneratedFileImpl) generateFileContent(object ObjectUnionType, schema schema_model.GoRelSchema, enumNames []string, modelNames []string, modulePath string) error {
+	var content string
 	var err error
-	var referenceModels, referenceEnums []string
 
-	if object.fileType == MODEL {
-		structString, referenceModels, referenceEnums, err = g.generateStructModel(object.model, enumNames, modelNames)
-	} else {
-		structString = g.generateEnum(object.enum)
+	switch object.fileType {
+	case MODEL:
+		var data modelTemplateData
+		data, err = g.generateStructModel(object.model, enumNames, modelNames, modulePath, schema.Generator)
+		if err != nil {
+			return err
+		}
+		content, err = renderTemplate("model.go.tmpl", fmt.Sprintf("%s.go", object.model.Name), data)
+	case OPTIONAL:
+		content, err = renderTemplate("optional.go.tmpl", "Optional.go", nil)
+	case CLIENT:
+		var data clientTemplateData
+		data, err = g.generateClient(schema, enumNames, modulePath)
+		if err != nil {
+			return err
+		}
+		content, err = renderTemplate("client.go.tmpl", "client.go", data)
+	case CLIENT_FILTERS:
+		content, err = renderTemplate("client_filters.go.tmpl", "filters.go", clientTemplateData{Provider: schema.Connection.Provider})
+	case CLIENT_MODEL:
+		var data clientModelTemplateData
+		data, err = g.generateClientModel(object.model, schema, enumNames, modulePath)
+		if err != nil {
+			return err
+		}
+		content, err = renderTemplate("client_model.go.tmpl", fmt.Sprintf("%s.go", object.model.Name), data)
+	default:
+		content, err = renderTemplate("enum.go.tmpl", fmt.Sprintf("%s.go", object.enum.Name), g.generateEnum(object.enum, schema.Generator))
 	}
 
 	if err != nil {
 		return err
 	}
-	importString := g.generateImports(referenceEnums, referenceModels, projectName)
-	g.content = fmt.Sprintf("%s\n%s", importString, structString)
+	g.content = content
 	return nil
 }
 
-func (g *GoRelGeneratedFileImpl) generateImports(referenceEnums []string, referenceModels []string, projectName string) string {
-	importString := ""
+func (g *GoRelGeneratedFileImpl) generateStructModel(model schema_model.Model, enumNames []string, modelNames []string, modulePath string, generator schema_model.Generator) (modelTemplateData, error) {
+	data := modelTemplateData{Name: model.Name}
+	strategy := generator.GetNullableStrategy()
+
+	for _, property := range model.Properties {
+		field := fieldTemplateData{
+			Name: generator.FieldName(property.Name),
+			Tag:  fieldTag(property, generator, false),
+		}
+
+		goLangType, propertyImports, isValidGoLangType := property.GetGoLangType(strategy)
+		if isValidGoLangType {
+			data.Imports = append(data.Imports, propertyImports...)
+			field.Type = goLangType
+			data.Fields = append(data.Fields, field)
+			continue
+		}
+
+		enumName := strings.TrimSuffix(goLangType, "?")
+		if slices.Contains(enumNames, enumName) {
+			data.Imports = append(data.Imports, fmt.Sprintf("%s/gorel/enums", modulePath))
+			field.Type = fmt.Sprintf("enums.%s", enumName)
+			if strings.HasSuffix(goLangType, "?") {
+				field.Type = schema_model.NullableGoType(field.Type, strategy)
+			}
+			data.Fields = append(data.Fields, field)
+			continue
+		}
+
+		// relation fields live in the same package and are marked, so they are not treated as columns
+		if relatedModelName := strings.TrimSuffix(goLangType, "[]"); relatedModelName != goLangType && slices.Contains(modelNames, relatedModelName) {
+			field.Type = fmt.Sprintf("[]%s", relatedModelName)
+			field.Tag = fieldTag(property, generator, true)
+			data.Fields = append(data.Fields, field)
+			continue
+		}
+		if relatedModelName := strings.TrimSuffix(goLangType, "?"); slices.Contains(modelNames, relatedModelName) {
+			field.Type = fmt.Sprintf("*%s", relatedModelName)
+			field.Tag = fieldTag(property, generator, true)
+			data.Fields = append(data.Fields, field)
+			continue
+		}
 
-	if g.fileType == MODEL {
-		importString = "package models\n\n"
-	} else {
-		importString = "package enums\n\n"
+		return modelTemplateData{}, errors.New(fmt.Sprintf("Property with name %s has wrong type %s", property.Name, property.Type))
 	}
 
-	if len(referenceEnums) != 0 && g.fileType != ENUM {
-		importString += fmt.Sprintf("import \"%s/gorel/enums\"\n", projectName)
+	slices.Sort(data.Imports)
+	data.Imports = slices.Compact(data.Imports)
+	return data, nil
+}
+
+// fieldTag builds struct tag of the property field: gorel tag with column name (relations are marked, because they are not columns)
+// and tags configured in the generator block
+func fieldTag(property schema_model.Property, generator schema_model.Generator, isRelation bool) string {
+	gorelTag := property.Name
+	if isRelation {
+		gorelTag += ",relation"
 	}
+	tags := []string{fmt.Sprintf("gorel:\"%s\"", gorelTag)}
 
-	if len(referenceModels) != 0 && g.fileType != MODEL {
-		importString += fmt.Sprintf("import \"%s/gorel/models\"\n", projectName)
+	isNullable := strings.HasSuffix(property.Type, "?") || isRelation
+	for _, tag := range generator.Tags {
+		var value string
+		if tag.Name == "validate" {
+			// relations are filled only when they are included, so they are never validated
+			switch {
+			case isRelation:
+				continue
+			case isNullable:
+				value = "omitempty"
+			// zero values of numbers and booleans are valid values, so only strings and dates are required
+			case property.Default == "" && (property.Type == schema_model.String || property.Type == schema_model.DateTime):
+				value = "required"
+			default:
+				continue
+			}
+			tags = append(tags, fmt.Sprintf("%s:\"%s\"", tag.Name, value))
+			continue
+		}
+
+		// relations are not columns, so libraries that map columns by db tag should skip them
+		if tag.Name == "db" && isRelation {
+			tags = append(tags, "db:\"-\"")
+			continue
+		}
+
+		switch tag.GetNaming() {
+		case schema_model.Snake:
+			value = naming.Snake(property.Name)
+		case schema_model.Original:
+			value = property.Name
+		default:
+			value = naming.Camel(property.Name)
+		}
+		if omitEmpty := tag.GetOmitEmpty(); omitEmpty == schema_model.Always || (omitEmpty == schema_model.NullableFields && isNullable) {
+			value += ",omitempty"
+		}
+		tags = append(tags, fmt.Sprintf("%s:\"%s\"", tag.Name, value))
 	}
+	return strings.Join(tags, " ")
+}
 
-	return importString
+func (g *GoRelGeneratedFileImpl) generateClient(schema schema_model.GoRelSchema, enumNames []string, modulePath string) (clientTemplateData, error) {
+	data := clientTemplateData{Provider: schema.Connection.Provider}
+	for _, model := range schema.Models {
+		modelData, err := g.generateClientModel(model, schema, enumNames, modulePath)
+		if err != nil {
+			return clientTemplateData{}, err
+		}
+		data.Models = append(data.Models, modelData)
+	}
+	return data, nil
 }
 
-func (g *GoRelGeneratedFileImpl) generateStructModel(model schema_model.Model, enumNames []string, modelNames []string) (structString string, referenceModels []string, referenceEnums []string, err error) {
-	caser := cases.Title(language.English)
-	structString = fmt.Sprintf("type %s struct{\n", model.Name)
+// generateClientModel collects columns of the model (scalar and enum properties), relation properties are not stored in the table
+func (g *GoRelGeneratedFileImpl) generateClientModel(model schema_model.Model, schema schema_model.GoRelSchema, enumNames []string, modulePath string) (clientModelTemplateData, error) {
+	strategy := schema.Generator.GetNullableStrategy()
+	data := clientModelTemplateData{
+		Name:       model.Name,
+		Table:      model.Name,
+		Variable:   naming.LowerFirst(model.Name),
+		Repository: naming.Pluralize(model.Name),
+		Imports:    []string{fmt.Sprintf("%s/gorel/models", modulePath)},
+	}
+
+	primaryKey := model.GetPrimaryKey()
 	for _, property := range model.Properties {
-		if property.RelationField != "" {
+		// columns of the composite primary key are unique only together, they are selected by PrimaryKey field of WhereUnique
+		isSingleKey := len(primaryKey) == 1 && primaryKey[0] == property.Name
+		column := clientColumnTemplateData{
+			Column:       property.Name,
+			Field:        schema.Generator.FieldName(property.Name),
+			IsArray:      strings.HasSuffix(property.Type, "[]"),
+			IsUnique:     isSingleKey || property.Unique,
+			IsRequired:   !strings.HasSuffix(property.Type, "?") && !strings.HasSuffix(property.Type, "[]") && property.Default == "",
+			GenerateUuid: schema.Connection.Provider == schema_model.MySQL && isSingleKey && property.Default == "uuid()",
+		}
+
+		goLangType, propertyImports, isValidGoLangType := property.GetGoLangType(strategy)
+		enumName := strings.TrimSuffix(goLangType, "?")
+		switch {
+		case isValidGoLangType:
+			data.Imports = append(data.Imports, propertyImports...)
+			column.Type = goLangType
+			if strings.HasPrefix(goLangType, "Optional[") {
+				column.Type = fmt.Sprintf("models.%s", goLangType)
+			}
+			// filters take plain values, so sql.Null* and pointer types are unwrapped
+			baseType, baseImports, _ := property.GetGoLangType(schema_model.Pointer)
+			data.Imports = append(data.Imports, baseImports...)
+			column.FilterType = clientFilterType(model.Name, property, strings.TrimPrefix(strings.TrimPrefix(baseType, "*"), "[]"))
+		case slices.Contains(enumNames, enumName):
+			data.Imports = append(data.Imports, fmt.Sprintf("%s/gorel/enums", modulePath))
+			column.Type = fmt.Sprintf("enums.%s", enumName)
+			column.FilterType = clientFilterType(model.Name, property, column.Type)
+			if strings.HasSuffix(goLangType, "?") {
+				column.Type = schema_model.NullableGoType(column.Type, strategy)
+				if strategy == schema_model.Optional {
+					column.Type = fmt.Sprintf("models.%s", column.Type)
+				}
+			}
+		default:
 			continue
 		}
-		goLangType, isValidGoLangType := property.GetGoLangType()
-		if !isValidGoLangType {
-			if slices.Contains(enumNames, goLangType) {
-				referenceEnums = append(referenceEnums, property.Type)
-				structString += fmt.Sprintf("\t%s enums.%s\n `gorel:\"%s\"`", caser.String(property.Name), goLangType, property.Name)
+
+		data.Columns = append(data.Columns, column)
+	}
+
+	// key columns follow the order of the primary key, not the order of the properties
+	for _, name := range primaryKey {
+		index := slices.IndexFunc(data.Columns, func(column clientColumnTemplateData) bool { return column.Column == name })
+		if index != -1 {
+			data.KeyColumns = append(data.KeyColumns, data.Columns[index])
+		}
+	}
+	if len(data.KeyColumns) == 0 || len(data.KeyColumns) != len(primaryKey) {
+		return clientModelTemplateData{}, errors.New(fmt.Sprintf("Model with name %s has no id property", model.Name))
+	}
+
+	data.Relations = clientRelations(model, schema)
+
+	slices.Sort(data.Imports)
+	data.Imports = slices.Compact(data.Imports)
+	return data, nil
+}
+
+// clientRelations collects relation properties of the model. References (e.g. Todo.user) are matched by their relationField,
+// back references (e.g. User.todos) are matched by relationField of the paired property that references the model,
+// many-to-many relations (e.g. User.videos) are matched by primary keys stored in the join table.
+func clientRelations(model schema_model.Model, schema schema_model.GoRelSchema) []clientRelationTemplateData {
+	var relations []clientRelationTemplateData
+	for _, property := range model.Properties {
+		relatedName := strings.TrimSuffix(strings.TrimSuffix(property.Type, "[]"), "?")
+		for _, related := range schema.Models {
+			if related.Name != relatedName {
 				continue
 			}
 
-			clearedModelName := ""
-			if strings.Contains(goLangType, "[]") {
-				clearedModelName = goLangType[:len(goLangType)-2]
-			} else {
-				clearedModelName = goLangType
+			relation := clientRelationTemplateData{
+				Field:      schema.Generator.FieldName(property.Name),
+				Model:      related.Name,
+				Variable:   naming.LowerFirst(related.Name),
+				Repository: naming.Pluralize(related.Name),
+				IsList:     strings.HasSuffix(property.Type, "[]"),
 			}
 
-			if slices.Contains(modelNames, clearedModelName) {
-				referenceModels = append(referenceModels, property.Type)
-				structString += fmt.Sprintf("\t%s []%s `gorel:\"%s\"`\n", caser.String(property.Name), clearedModelName, property.Name)
-				continue
+			if joinTable, isJoinTable := model.GetJoinTable(property, related); isJoinTable {
+				relation.Column = related.GetPrimaryKey()[0]
+				relation.Key = schema.Generator.FieldName(model.GetPrimaryKey()[0])
+				relation.RelatedKey = schema.Generator.FieldName(related.GetPrimaryKey()[0])
+				relation.JoinTable = joinTable.Name
+				relation.JoinColumn = joinTable.Column
+				relation.JoinRelatedColumn = joinTable.RelatedColumn
+				relations = append(relations, relation)
+				break
+			}
+
+			if property.RelationField != "" {
+				relation.Column = property.ReferenceField
+				relation.Key = schema.Generator.FieldName(property.RelationField)
+				relation.RelatedKey = schema.Generator.FieldName(property.ReferenceField)
+				relation.IsReference = true
+				relations = append(relations, relation)
+				break
 			}
 
-			return "", nil, nil, errors.New(fmt.Sprintf("Property with name %s has wrong type %s", property.Name, property.Type))
+			for _, reference := range model.FindRelationPairs(property, related) {
+				if reference.RelationField == "" || strings.HasSuffix(reference.Type, "[]") {
+					continue
+				}
+				relation.Column = reference.RelationField
+				relation.Key = schema.Generator.FieldName(reference.ReferenceField)
+				relation.RelatedKey = schema.Generator.FieldName(reference.RelationField)
+				relations = append(relations, relation)
+				break
+			}
+			break
 		}
-		structString += fmt.Sprintf("\t%s %s `gorel:\"%s\"`\n", caser.String(property.Name), goLangType, property.Name)
 	}
-	structString += "}"
-	return structString, referenceModels, referenceEnums, nil
+	return relations
 }
 
-func (g *GoRelGeneratedFileImpl) generateEnum(enum schema_model.Enum) string {
-	enumString := fmt.Sprintf("type %s string\n\nconst (\n", enum.Name)
-	for i, value := range enum.Values {
-		if i == 0 {
-			enumString += fmt.Sprintf("\t%s %s = \"%s\"\n", value, enum.Name, value)
-			continue
-		}
-		enumString += fmt.Sprintf("\t%s = \"%s\"\n", value, value)
+// clientFilterType returns type of the filter generated for the column, baseType is go type of the single non-null value
+func clientFilterType(modelName string, property schema_model.Property, baseType string) string {
+	model := fmt.Sprintf("models.%s", modelName)
+	if strings.HasSuffix(property.Type, "[]") {
+		return fmt.Sprintf("ArrayFilter[%s, %s]", model, baseType)
+	}
+
+	prefix := ""
+	if strings.HasSuffix(property.Type, "?") {
+		prefix = "Nullable"
+	}
+	switch schema_model.PropertyType(strings.TrimSuffix(property.Type, "?")) {
+	case schema_model.String:
+		return fmt.Sprintf("%sStringFilter[%s]", prefix, model)
+	case schema_model.Int, schema_model.Float, schema_model.DateTime:
+		return fmt.Sprintf("%sOrderedFilter[%s, %s]", prefix, model, baseType)
+	default:
+		return fmt.Sprintf("%sFilter[%s, %s]", prefix, model, baseType)
+	}
+}
+
+func (g *GoRelGeneratedFileImpl) generateEnum(enum schema_model.Enum, generator schema_model.Generator) enumTemplateData {
+	data := enumTemplateData{Name: enum.Name, Variable: naming.LowerFirst(enum.Name)}
+	for _, value := range enum.Values {
+		data.Values = append(data.Values, enumValueTemplateData{Name: generator.EnumValueName(value), Value: value})
 	}
-	enumString += ")"
-	return enumString
+	return data
 }
 
 func (g *GoRelGeneratedFileImpl) WriteFSAsync(c chan error, syncGroup *sync.WaitGroup) {
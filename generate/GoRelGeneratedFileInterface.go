@@ -1,9 +1,15 @@
 package generate
 
-import "sync"
+import (
+	"GoRelCli/models/schema_model"
+	"sync"
+)
 
 type GoRelGeneratedFileInterface interface {
-	Create(object ObjectUnionType, enumNames []string, modelNames []string, projectName string, projectPath string) error
+	Create(object ObjectUnionType, schema schema_model.GoRelSchema, enumNames []string, modelNames []string, modulePath string, projectPath string) error
+	Path() string
+	// SetHeader sets text that is written before the content of the file
+	SetHeader(header string)
 	WriteFS() error
 	WriteFSAsync(c chan error, syncGroup *sync.WaitGroup)
 	Log()
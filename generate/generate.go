@@ -8,7 +8,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 )
@@ -26,19 +28,53 @@ func getPathArguments(args ...string) (schemaPath string, projectPath string, er
 	return args[0], projectPath, nil
 }
 
-func getProjectName(path string) (string, error) {
-	if !strings.Contains(path, "\\") {
-		return "", errors.New("can't get project name from path")
-	}
-	absolutePath, err := filepath.Abs(path)
+// getModulePath finds go.mod in the project folder or in its parents and returns import path of the project folder
+// (e.g. "example.com/app/internal" for project folder internal inside module example.com/app)
+func getModulePath(projectPath string) (string, error) {
+	absolutePath, err := filepath.Abs(projectPath)
 	if err != nil {
 		return "", err
 	}
-	splittedPath := strings.Split(absolutePath, "\\")
-	return splittedPath[len(splittedPath)-1], nil
+
+	for folderPath := absolutePath; ; folderPath = filepath.Dir(folderPath) {
+		content, err := os.ReadFile(filepath.Join(folderPath, "go.mod"))
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+
+		if err == nil {
+			moduleName, err := parseModuleName(string(content))
+			if err != nil {
+				return "", err
+			}
+
+			relativePath, err := filepath.Rel(folderPath, absolutePath)
+			if err != nil {
+				return "", err
+			}
+			if relativePath == "." {
+				return moduleName, nil
+			}
+			return path.Join(moduleName, filepath.ToSlash(relativePath)), nil
+		}
+
+		if filepath.Dir(folderPath) == folderPath {
+			return "", errors.New(fmt.Sprintf("can't find go.mod in %s or its parent folders", absolutePath))
+		}
+	}
 }
 
-func createFileObjects(schema schema_model.GoRelSchema, modelNames []string, enumNames []string, projectName string, projectPath string) ([]GoRelGeneratedFileInterface, error) {
+func parseModuleName(goMod string) (string, error) {
+	for _, line := range strings.Split(goMod, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], "\"`"), nil
+		}
+	}
+	return "", errors.New("can't find module directive in go.mod")
+}
+
+func createFileObjects(schema schema_model.GoRelSchema, modelNames []string, enumNames []string, modulePath string, projectPath string, schemaHash string) ([]GoRelGeneratedFileInterface, error) {
 	var fileObjects []GoRelGeneratedFileInterface
 	for _, model := range schema.Models {
 		object := ObjectUnionType{
@@ -46,7 +82,45 @@ func createFileObjects(schema schema_model.GoRelSchema, modelNames []string, enu
 			model:    model,
 		}
 		fileObject := GoRelGeneratedFileImpl{}
-		if err := fileObject.Create(object, enumNames, modelNames, projectName, projectPath); err != nil {
+		if err := fileObject.Create(object, schema, enumNames, modelNames, modulePath, projectPath); err != nil {
+			return nil, err
+		}
+		fileObjects = append(fileObjects, &fileObject)
+		fileObject.Log()
+	}
+
+	for _, model := range schema.Models {
+		object := ObjectUnionType{
+			fileType: CLIENT_MODEL,
+			model:    model,
+		}
+		fileObject := GoRelGeneratedFileImpl{}
+		if err := fileObject.Create(object, schema, enumNames, modelNames, modulePath, projectPath); err != nil {
+			return nil, err
+		}
+		fileObjects = append(fileObjects, &fileObject)
+		fileObject.Log()
+	}
+
+	if len(schema.Models) != 0 {
+		fileObject := GoRelGeneratedFileImpl{}
+		if err := fileObject.Create(ObjectUnionType{fileType: CLIENT}, schema, enumNames, modelNames, modulePath, projectPath); err != nil {
+			return nil, err
+		}
+		fileObjects = append(fileObjects, &fileObject)
+		fileObject.Log()
+
+		filtersObject := GoRelGeneratedFileImpl{}
+		if err := filtersObject.Create(ObjectUnionType{fileType: CLIENT_FILTERS}, schema, enumNames, modelNames, modulePath, projectPath); err != nil {
+			return nil, err
+		}
+		fileObjects = append(fileObjects, &filtersObject)
+		filtersObject.Log()
+	}
+
+	if schema.Generator.GetNullableStrategy() == schema_model.Optional && len(schema.Models) != 0 {
+		fileObject := GoRelGeneratedFileImpl{}
+		if err := fileObject.Create(ObjectUnionType{fileType: OPTIONAL}, schema, enumNames, modelNames, modulePath, projectPath); err != nil {
 			return nil, err
 		}
 		fileObjects = append(fileObjects, &fileObject)
@@ -59,16 +133,76 @@ func createFileObjects(schema schema_model.GoRelSchema, modelNames []string, enu
 			enum:     enum,
 		}
 		fileObject := GoRelGeneratedFileImpl{}
-		if err := fileObject.Create(object, enumNames, modelNames, projectName, projectPath); err != nil {
+		if err := fileObject.Create(object, schema, enumNames, modelNames, modulePath, projectPath); err != nil {
 			return nil, err
 		}
 		fileObjects = append(fileObjects, &fileObject)
 		fileObject.Log()
 	}
 
+	customFileObjects, err := createCustomFileObjects(schema, modelNames, enumNames, modulePath, projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// files of custom templates replace built-in files with the same path
+	fileObjects = slices.DeleteFunc(fileObjects, func(fileObject GoRelGeneratedFileInterface) bool {
+		return slices.ContainsFunc(customFileObjects, func(customFileObject GoRelGeneratedFileInterface) bool {
+			return customFileObject.Path() == fileObject.Path()
+		})
+	})
+	fileObjects = append(fileObjects, customFileObjects...)
+
+	for _, fileObject := range fileObjects {
+		fileObject.SetHeader(generatedFileHeader(fileObject.Path(), schemaHash))
+	}
 	return fileObjects, nil
 }
 
+// createCustomFileObjects renders templates from the generator block, template paths should be already resolved from the schema folder
+func createCustomFileObjects(schema schema_model.GoRelSchema, modelNames []string, enumNames []string, modulePath string, projectPath string) ([]GoRelGeneratedFileInterface, error) {
+	var fileObjects []GoRelGeneratedFileInterface
+	for _, template := range schema.Generator.Templates {
+		var objects []ObjectUnionType
+		switch template.GetScope() {
+		case schema_model.ModelScope:
+			for _, model := range schema.Models {
+				objects = append(objects, ObjectUnionType{template: template, model: model})
+			}
+		case schema_model.EnumScope:
+			for _, enum := range schema.Enums {
+				objects = append(objects, ObjectUnionType{template: template, enum: enum})
+			}
+		default:
+			objects = append(objects, ObjectUnionType{template: template})
+		}
+
+		for _, object := range objects {
+			fileObject := GoRelCustomFileImpl{}
+			if err := fileObject.Create(object, schema, enumNames, modelNames, modulePath, projectPath); err != nil {
+				return nil, err
+			}
+			fileObjects = append(fileObjects, &fileObject)
+			fileObject.Log()
+		}
+	}
+	return fileObjects, nil
+}
+
+// resolveTemplatePaths makes paths of custom templates absolute, relative paths are resolved from the folder of the schema
+func resolveTemplatePaths(schema *schema_model.GoRelSchema, schemaPath string) error {
+	schemaFolder, err := filepath.Abs(filepath.Dir(schemaPath))
+	if err != nil {
+		return err
+	}
+	for index, template := range schema.Generator.Templates {
+		if !filepath.IsAbs(template.Path) {
+			schema.Generator.Templates[index].Path = filepath.Join(schemaFolder, filepath.FromSlash(template.Path))
+		}
+	}
+	return nil
+}
+
 func checkFolder(path string) (bool, error) {
 	if _, err := os.Stat(path); err != nil {
 		if os.IsNotExist(err) {
@@ -89,17 +223,18 @@ func checkFolders(modelNames []string, enumNames []string, projectPath string) e
 	var folderPath string
 
 	if len(modelNames) != 0 {
-		folderPath = fmt.Sprintf("%s\\gorel\\models", filePath)
-		if isValid, err := checkFolder(folderPath); err != nil {
-			return err
-		} else if !isValid {
-			if err := os.MkdirAll(folderPath, os.ModePerm); err != nil {
+		for _, folderPath = range []string{filepath.Join(filePath, "gorel", "models"), filepath.Join(filePath, "gorel", "client")} {
+			if isValid, err := checkFolder(folderPath); err != nil {
 				return err
+			} else if !isValid {
+				if err := os.MkdirAll(folderPath, os.ModePerm); err != nil {
+					return err
+				}
 			}
 		}
 	}
 	if len(enumNames) != 0 {
-		folderPath = fmt.Sprintf("%s\\gorel\\enums", filePath)
+		folderPath = filepath.Join(filePath, "gorel", "enums")
 		if isValid, err := checkFolder(folderPath); err != nil {
 			return err
 		} else if !isValid {
@@ -144,7 +279,7 @@ func createFilesAsync(fileObjects []GoRelGeneratedFileInterface) error {
 func Generate(args ...string) error {
 	var schemaPath, projectPath string
 
-	if err := logger.LogStep("get project name", func() error {
+	if err := logger.LogStep("get path arguments", func() error {
 		schemaPathInn, projectPathInn, err := getPathArguments(args...)
 		if err != nil {
 			return err
@@ -157,14 +292,14 @@ func Generate(args ...string) error {
 	}
 
 	var goRelSchema schema_model.GoRelSchema
-	var projectName string
+	var modulePath string
 
-	if err := logger.LogStep("get project name", func() error {
-		projectNameInn, err := getProjectName(projectPath)
+	if err := logger.LogStep("get module path", func() error {
+		modulePathInn, err := getModulePath(projectPath)
 		if err != nil {
 			return err
 		}
-		projectName = projectNameInn
+		modulePath = modulePathInn
 		return nil
 	}); err != nil {
 		return err
@@ -193,10 +328,34 @@ func Generate(args ...string) error {
 		return err
 	}
 
+	if err := logger.LogStep("resolve template paths", func() error {
+		return resolveTemplatePaths(&goRelSchema, schemaPath)
+	}); err != nil {
+		return err
+	}
+
+	var schemaHash string
+	var previousManifest manifest
+
+	if err := logger.LogStep("read manifest", func() error {
+		schemaHashInn, err := hashSchema(schemaPath)
+		if err != nil {
+			return err
+		}
+		previousManifestInn, err := readManifest(projectPath)
+		if err != nil {
+			return err
+		}
+		schemaHash, previousManifest = schemaHashInn, previousManifestInn
+		return nil
+	}); err != nil {
+		return err
+	}
+
 	var fileObjects []GoRelGeneratedFileInterface
 
 	if err := logger.LogStep("generate file objects", func() error {
-		fileObjectsInn, err := createFileObjects(goRelSchema, modelNames, enumNames, projectName, projectPath)
+		fileObjectsInn, err := createFileObjects(goRelSchema, modelNames, enumNames, modulePath, projectPath, schemaHash)
 		if err != nil {
 			return err
 		}
@@ -215,6 +374,12 @@ func Generate(args ...string) error {
 		return err
 	}
 
+	if err := logger.LogStep("check generated files", func() error {
+		return checkGeneratedFiles(fileObjects, projectPath, previousManifest)
+	}); err != nil {
+		return err
+	}
+
 	if err := logger.LogStep("create files", func() error {
 		if err := createFilesAsync(fileObjects); err != nil {
 			return err
@@ -224,5 +389,15 @@ func Generate(args ...string) error {
 		return err
 	}
 
+	if err := logger.LogStep("remove stale files", func() error {
+		currentManifest, err := removeStaleFiles(fileObjects, projectPath, previousManifest, schemaHash)
+		if err != nil {
+			return err
+		}
+		return writeManifest(projectPath, currentManifest)
+	}); err != nil {
+		return err
+	}
+
 	return nil
 }
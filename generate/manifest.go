@@ -0,0 +1,167 @@
+package generate
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// generatedFileComment marks generated go files (https://go.dev/s/generatedcode), only files with it are overwritten and removed
+const generatedFileComment = "// Code generated by GoRelCli. DO NOT EDIT."
+
+// manifest lists files written by the last generate run, paths are relative to the project folder
+type manifest struct {
+	SchemaHash string   `json:"schemaHash"`
+	Files      []string `json:"files"`
+}
+
+func getManifestPath(projectPath string) string {
+	return filepath.Join(projectPath, "gorel", "manifest.json")
+}
+
+// hashSchema returns sha256 of the schema file, it is written to headers of generated files
+func hashSchema(schemaPath string) (string, error) {
+	content, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// generatedFileHeader returns header of generated go files, other files have no header because their comment syntax is unknown
+func generatedFileHeader(path string, schemaHash string) string {
+	if filepath.Ext(path) != ".go" {
+		return ""
+	}
+	return fmt.Sprintf("%s\n// Schema hash: %s\n\n", generatedFileComment, schemaHash)
+}
+
+func readManifest(projectPath string) (manifest, error) {
+	var result manifest
+	content, err := os.ReadFile(getManifestPath(projectPath))
+	if os.IsNotExist(err) {
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	if err := json.Unmarshal(content, &result); err != nil {
+		return result, errors.New(fmt.Sprintf("can't read manifest %s: %s", getManifestPath(projectPath), err))
+	}
+	return result, nil
+}
+
+func writeManifest(projectPath string, result manifest) error {
+	content, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(getManifestPath(projectPath)), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(getManifestPath(projectPath), append(content, '\n'), 0666)
+}
+
+// isGeneratedFile checks if file was written by generate. Go files should start with the generated file comment,
+// other files have no header, so they should be listed in the manifest.
+func isGeneratedFile(path string, relativePath string, previous manifest) (bool, error) {
+	if filepath.Ext(path) != ".go" {
+		return slices.Contains(previous.Files, relativePath), nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		return false, scanner.Err()
+	}
+	return strings.TrimSpace(scanner.Text()) == generatedFileComment, nil
+}
+
+// relativeFilePath returns slash separated path of the file inside the project folder, it is stored in the manifest
+func relativeFilePath(projectPath string, path string) (string, error) {
+	relativePath, err := filepath.Rel(projectPath, path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(relativePath), nil
+}
+
+// checkGeneratedFiles refuses to overwrite existing files that were not written by generate
+func checkGeneratedFiles(fileObjects []GoRelGeneratedFileInterface, projectPath string, previous manifest) error {
+	for _, fileObject := range fileObjects {
+		relativePath, err := relativeFilePath(projectPath, fileObject.Path())
+		if err != nil {
+			return err
+		}
+
+		if _, err := os.Stat(fileObject.Path()); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		isGenerated, err := isGeneratedFile(fileObject.Path(), relativePath, previous)
+		if err != nil {
+			return err
+		}
+		if !isGenerated {
+			return errors.New(fmt.Sprintf("file %s already exists and was not generated by GoRelCli, move it or remove it to generate the schema", relativePath))
+		}
+	}
+	return nil
+}
+
+// removeStaleFiles removes files of the previous manifest that are not generated anymore (e.g. files of removed models)
+// and returns manifest of the current files
+func removeStaleFiles(fileObjects []GoRelGeneratedFileInterface, projectPath string, previous manifest, schemaHash string) (manifest, error) {
+	current := manifest{SchemaHash: schemaHash}
+	for _, fileObject := range fileObjects {
+		relativePath, err := relativeFilePath(projectPath, fileObject.Path())
+		if err != nil {
+			return manifest{}, err
+		}
+		current.Files = append(current.Files, relativePath)
+	}
+	slices.Sort(current.Files)
+
+	for _, relativePath := range previous.Files {
+		if slices.Contains(current.Files, relativePath) {
+			continue
+		}
+
+		path := filepath.Join(projectPath, filepath.FromSlash(relativePath))
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return manifest{}, err
+		}
+
+		isGenerated, err := isGeneratedFile(path, relativePath, previous)
+		if err != nil {
+			return manifest{}, err
+		}
+		if !isGenerated {
+			fmt.Println(fmt.Sprintf("File %s is not generated anymore, but it was changed, so it is not removed", relativePath))
+			continue
+		}
+
+		if err := os.Remove(path); err != nil {
+			return manifest{}, err
+		}
+		fmt.Println(fmt.Sprintf("Removed stale file %s", relativePath))
+	}
+	return current, nil
+}
@@ -0,0 +1,148 @@
+package generate
+
+import (
+	"GoRelCli/models/schema_model"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+var templates = template.Must(template.ParseFS(templatesFS, "templates/*.tmpl"))
+
+type fieldTemplateData struct {
+	Name string
+	Type string
+	Tag  string
+}
+
+type modelTemplateData struct {
+	Name    string
+	Imports []string
+	Fields  []fieldTemplateData
+}
+
+type enumValueTemplateData struct {
+	Name  string
+	Value string
+}
+
+type enumTemplateData struct {
+	Name     string
+	Variable string
+	Values   []enumValueTemplateData
+}
+
+type clientColumnTemplateData struct {
+	Column       string
+	Field        string
+	Type         string
+	FilterType   string
+	IsArray      bool
+	IsUnique     bool
+	IsRequired   bool
+	GenerateUuid bool
+}
+
+// Value returns expression that converts go value to the value passed to the driver
+func (c clientColumnTemplateData) Value(expression string) string {
+	if c.IsArray {
+		return fmt.Sprintf("arrayValue(%s)", expression)
+	}
+	return expression
+}
+
+// FilterConstructor returns instantiated function that creates filter of the column
+func (c clientColumnTemplateData) FilterConstructor() string {
+	return fmt.Sprintf("new%s", c.FilterType)
+}
+
+// Scanner returns scan destination of the column inside model variable
+func (c clientColumnTemplateData) Scanner(model string) string {
+	if c.IsArray {
+		return fmt.Sprintf("arrayScanner(&%s.%s)", model, c.Field)
+	}
+	return fmt.Sprintf("&%s.%s", model, c.Field)
+}
+
+// clientRelationTemplateData describes relation field of the model that is loaded by Include<Field> method
+type clientRelationTemplateData struct {
+	Field string
+	// Model, Variable and Repository are names of the related model
+	Model      string
+	Variable   string
+	Repository string
+	// Column of the related table that is matched with Key field of the model
+	Column     string
+	Key        string
+	RelatedKey string
+	IsList     bool
+	// IsReference is true if the model stores relation column (e.g. Todo.user), such records can't be created by nested create
+	IsReference bool
+	// JoinTable is set for implicit many-to-many relations, JoinColumn references the model and JoinRelatedColumn references the related model
+	JoinTable         string
+	JoinColumn        string
+	JoinRelatedColumn string
+}
+
+// IsNestedCreate is true if related records can be created together with the model
+func (r clientRelationTemplateData) IsNestedCreate() bool {
+	return !r.IsReference && r.JoinTable == ""
+}
+
+type clientModelTemplateData struct {
+	Name       string
+	Table      string
+	Variable   string
+	Repository string
+	Imports    []string
+	Columns    []clientColumnTemplateData
+	KeyColumns []clientColumnTemplateData
+	Relations  []clientRelationTemplateData
+}
+
+// HasCompositeKey is true if primary key of the model has several columns
+func (m clientModelTemplateData) HasCompositeKey() bool {
+	return len(m.KeyColumns) > 1
+}
+
+// HasNestedCreate is true if related records can be created together with the model
+func (m clientModelTemplateData) HasNestedCreate() bool {
+	for _, relation := range m.Relations {
+		if relation.IsNestedCreate() {
+			return true
+		}
+	}
+	return false
+}
+
+type clientTemplateData struct {
+	Provider schema_model.Provider
+	Models   []clientModelTemplateData
+}
+
+// renderTemplate executes template with provided data and formats the result with gofmt.
+// Formatting fails if generated code is not valid go code, so broken files are never written.
+func renderTemplate(name string, fileName string, data any) (string, error) {
+	var buffer bytes.Buffer
+	if err := templates.ExecuteTemplate(&buffer, name, data); err != nil {
+		return "", errors.New(fmt.Sprintf("can't execute template %s for %s: %s", name, fileName, err))
+	}
+
+	return formatSource(fileName, buffer.Bytes())
+}
+
+// formatSource formats generated go code with gofmt
+func formatSource(fileName string, content []byte) (string, error) {
+	source, err := format.Source(content)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("generated file %s is not valid go code: %s\n%s", fileName, err, string(content)))
+	}
+
+	return string(source), nil
+}
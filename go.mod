@@ -3,8 +3,12 @@ module GoRelCli
 go 1.21
 
 require (
+	github.com/go-sql-driver/mysql v1.7.1
 	github.com/joho/godotenv v1.5.1
 	github.com/lib/pq v1.10.9
+	github.com/mattn/go-sqlite3 v1.14.17
+	golang.org/x/term v0.15.0
 	gopkg.in/yaml.v3 v3.0.1
-	golang.org/x/text v0.14.0
 )
+
+require golang.org/x/sys v0.15.0 // indirect
@@ -0,0 +1,227 @@
+// Code generated by GoRelCli. DO NOT EDIT.
+// Schema hash: c84a19c22a205eb52d35f0561f869054b1373387b36e4391a482163ebca56280
+
+package client
+
+import (
+	"GoRelCli/gorel/models"
+	"context"
+	"slices"
+)
+
+// NoteWhereUnique selects one Note by unique fields, all set fields should match
+type NoteWhereUnique struct {
+	Id Field[string]
+}
+
+func (w NoteWhereUnique) condition() condition {
+	var conditions []condition
+	if value, isSet := w.Id.Get(); isSet {
+		conditions = append(conditions, equals("id", value))
+	}
+	return and(conditions)
+}
+
+// NoteWhereFields contains filters of Note columns
+type NoteWhereFields struct {
+	Id   StringFilter[models.Note]
+	Text StringFilter[models.Note]
+}
+
+// NoteWhere creates conditions for Note, e.g. NoteWhere.Id.Equals(value)
+var NoteWhere = NoteWhereFields{
+	Id:   newStringFilter[models.Note]("id"),
+	Text: newStringFilter[models.Note]("text"),
+}
+
+// NoteColumns contains columns of Note
+type NoteColumns struct {
+	Id   Column[models.Note]
+	Text Column[models.Note]
+}
+
+// NoteSelect contains columns that can be selected by FindMany
+var NoteSelect = NoteColumns{
+	Id:   Column[models.Note]{name: "id"},
+	Text: Column[models.Note]{name: "text"},
+}
+
+// NoteOrderBy contains columns that can be used to order Note records, e.g. NoteOrderBy.Id.Desc()
+var NoteOrderBy = NoteSelect
+
+// NoteCreate contains values of the new Note. Fields with default values and nullable fields can be omitted.
+type NoteCreate struct {
+	Id   Field[string]
+	Text string
+}
+
+func (d NoteCreate) values() []columnValue {
+	var values []columnValue
+	if value, isSet := d.Id.Get(); isSet {
+		values = append(values, columnValue{column: "id", value: value})
+	}
+	values = append(values, columnValue{column: "text", value: d.Text})
+	return values
+}
+
+// NoteUpdate contains values that should be changed, only set fields are updated
+type NoteUpdate struct {
+	Id   Field[string]
+	Text Field[string]
+}
+
+func (d NoteUpdate) values() []columnValue {
+	var values []columnValue
+	if value, isSet := d.Id.Get(); isSet {
+		values = append(values, columnValue{column: "id", value: value})
+	}
+	if value, isSet := d.Text.Get(); isSet {
+		values = append(values, columnValue{column: "text", value: value})
+	}
+	return values
+}
+
+// NoteFindManyArgs contains options of NoteRepository.FindMany.
+// Records should match every Where condition, Select limits loaded columns (all columns are loaded by default).
+type NoteFindManyArgs struct {
+	Where   []Where[models.Note]
+	OrderBy []OrderBy[models.Note]
+	Select  []Column[models.Note]
+	Take    int
+	Skip    int
+}
+
+var noteTable = table[models.Note]{
+	name:       "Note",
+	columns:    []string{"id", "text"},
+	keyColumns: []string{"id"},
+	key: func(model models.Note) []any {
+		return []any{model.Id}
+	},
+	fields: func(model *models.Note) []any {
+		return []any{&model.Id, &model.Text}
+	},
+}
+
+// NoteRepository runs queries on Note table
+type NoteRepository struct {
+	client   *Client
+	includes []func(ctx context.Context, records []models.Note) error
+}
+
+func (r *NoteRepository) include(load func(ctx context.Context, records []models.Note) error) *NoteRepository {
+	return &NoteRepository{client: r.client, includes: append(slices.Clip(r.includes), load)}
+}
+
+// load fills relation fields of records that are included into the repository
+func (r *NoteRepository) load(ctx context.Context, records []models.Note) error {
+	for _, load := range r.includes {
+		if err := load(ctx, records); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IncludeTodo returns repository that loads Todo of the returned Note records. Nested includes are applied to the loaded Todo records.
+func (r *NoteRepository) IncludeTodo(nested ...func(repository *TodoRepository) *TodoRepository) *NoteRepository {
+	related := r.client.Todos
+	for _, include := range nested {
+		related = include(related)
+	}
+	return r.include(func(ctx context.Context, records []models.Note) error {
+		return loadRelation(ctx, r.client.db, todoTable, "id", records,
+			func(model models.Note) any { return model.Id },
+			func(model models.Todo) any { return model.Id },
+			related.load,
+			func(model *models.Note, loaded []models.Todo) {
+				if len(loaded) != 0 {
+					model.Todo = &loaded[0]
+				}
+			},
+		)
+	})
+}
+
+// Create inserts new Note and returns it with values generated by the database
+func (r *NoteRepository) Create(ctx context.Context, data NoteCreate) (models.Note, error) {
+	result, err := r.create(ctx, data, nil)
+	if err != nil {
+		return result, err
+	}
+	return loadOne(ctx, result, r.load)
+}
+
+// create inserts data and related records, values replace columns of data (e.g. relation column of the nested create)
+func (r *NoteRepository) create(ctx context.Context, data NoteCreate, values []columnValue) (models.Note, error) {
+	result, err := create(ctx, r.client.db, noteTable, withValues(data.values(), values))
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// FindUnique returns Note selected by unique fields or nil if it doesn't exist
+func (r *NoteRepository) FindUnique(ctx context.Context, where NoteWhereUnique) (*models.Note, error) {
+	result, err := findUnique(ctx, r.client.db, noteTable, where.condition())
+	if err != nil || result == nil {
+		return result, err
+	}
+	loaded, err := loadOne(ctx, *result, r.load)
+	return &loaded, err
+}
+
+// FindMany returns every Note that matches args
+func (r *NoteRepository) FindMany(ctx context.Context, args NoteFindManyArgs) ([]models.Note, error) {
+	result, err := findMany(ctx, r.client.db, noteTable, findManyQuery[models.Note]{
+		where:   args.Where,
+		orderBy: args.OrderBy,
+		columns: args.Select,
+		take:    args.Take,
+		skip:    args.Skip,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, r.load(ctx, result)
+}
+
+// Update changes set fields of Note selected by unique fields and returns updated Note. ErrNotFound is returned if it doesn't exist.
+func (r *NoteRepository) Update(ctx context.Context, where NoteWhereUnique, data NoteUpdate) (models.Note, error) {
+	result, err := update(ctx, r.client.db, noteTable, where.condition(), data.values())
+	if err != nil {
+		return result, err
+	}
+	return loadOne(ctx, result, r.load)
+}
+
+// Delete deletes Note selected by unique fields and returns it. ErrNotFound is returned if it doesn't exist.
+func (r *NoteRepository) Delete(ctx context.Context, where NoteWhereUnique) (models.Note, error) {
+	return remove(ctx, r.client.db, noteTable, where.condition())
+}
+
+// Upsert updates Note selected by unique fields or creates it if it doesn't exist
+func (r *NoteRepository) Upsert(ctx context.Context, where NoteWhereUnique, createData NoteCreate, updateData NoteUpdate) (models.Note, error) {
+	var result models.Note
+	err := r.client.Transaction(ctx, func(tx *Client) error {
+		current, err := tx.Notes.FindUnique(ctx, where)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			result, err = tx.Notes.Create(ctx, createData)
+		} else {
+			result, err = tx.Notes.Update(ctx, where, updateData)
+		}
+		return err
+	})
+	if err != nil {
+		return result, err
+	}
+	return loadOne(ctx, result, r.load)
+}
+
+// Count returns number of Note records that match every where condition
+func (r *NoteRepository) Count(ctx context.Context, where ...Where[models.Note]) (int64, error) {
+	return count(ctx, r.client.db, noteTable, whereAll(where))
+}
@@ -0,0 +1,271 @@
+// Code generated by GoRelCli. DO NOT EDIT.
+// Schema hash: c84a19c22a205eb52d35f0561f869054b1373387b36e4391a482163ebca56280
+
+package client
+
+import (
+	"GoRelCli/gorel/models"
+	"context"
+	"slices"
+)
+
+// TodoWhereUnique selects one Todo by unique fields, all set fields should match
+type TodoWhereUnique struct {
+	Id Field[string]
+}
+
+func (w TodoWhereUnique) condition() condition {
+	var conditions []condition
+	if value, isSet := w.Id.Get(); isSet {
+		conditions = append(conditions, equals("id", value))
+	}
+	return and(conditions)
+}
+
+// TodoWhereFields contains filters of Todo columns
+type TodoWhereFields struct {
+	Id     StringFilter[models.Todo]
+	Title  StringFilter[models.Todo]
+	UserId OrderedFilter[models.Todo, int64]
+}
+
+// TodoWhere creates conditions for Todo, e.g. TodoWhere.Id.Equals(value)
+var TodoWhere = TodoWhereFields{
+	Id:     newStringFilter[models.Todo]("id"),
+	Title:  newStringFilter[models.Todo]("title"),
+	UserId: newOrderedFilter[models.Todo, int64]("userId"),
+}
+
+// TodoColumns contains columns of Todo
+type TodoColumns struct {
+	Id     Column[models.Todo]
+	Title  Column[models.Todo]
+	UserId Column[models.Todo]
+}
+
+// TodoSelect contains columns that can be selected by FindMany
+var TodoSelect = TodoColumns{
+	Id:     Column[models.Todo]{name: "id"},
+	Title:  Column[models.Todo]{name: "title"},
+	UserId: Column[models.Todo]{name: "userId"},
+}
+
+// TodoOrderBy contains columns that can be used to order Todo records, e.g. TodoOrderBy.Id.Desc()
+var TodoOrderBy = TodoSelect
+
+// TodoCreate contains values of the new Todo. Fields with default values and nullable fields can be omitted.
+// Related records are created in the same transaction, their relation fields are set to the created Todo.
+type TodoCreate struct {
+	Id     Field[string]
+	Title  string
+	UserId int64
+	Note   *NoteCreate
+}
+
+func (d TodoCreate) values() []columnValue {
+	var values []columnValue
+	if value, isSet := d.Id.Get(); isSet {
+		values = append(values, columnValue{column: "id", value: value})
+	}
+	values = append(values, columnValue{column: "title", value: d.Title})
+	values = append(values, columnValue{column: "userId", value: d.UserId})
+	return values
+}
+
+// TodoUpdate contains values that should be changed, only set fields are updated
+type TodoUpdate struct {
+	Id     Field[string]
+	Title  Field[string]
+	UserId Field[int64]
+}
+
+func (d TodoUpdate) values() []columnValue {
+	var values []columnValue
+	if value, isSet := d.Id.Get(); isSet {
+		values = append(values, columnValue{column: "id", value: value})
+	}
+	if value, isSet := d.Title.Get(); isSet {
+		values = append(values, columnValue{column: "title", value: value})
+	}
+	if value, isSet := d.UserId.Get(); isSet {
+		values = append(values, columnValue{column: "userId", value: value})
+	}
+	return values
+}
+
+// TodoFindManyArgs contains options of TodoRepository.FindMany.
+// Records should match every Where condition, Select limits loaded columns (all columns are loaded by default).
+type TodoFindManyArgs struct {
+	Where   []Where[models.Todo]
+	OrderBy []OrderBy[models.Todo]
+	Select  []Column[models.Todo]
+	Take    int
+	Skip    int
+}
+
+var todoTable = table[models.Todo]{
+	name:       "Todo",
+	columns:    []string{"id", "title", "userId"},
+	keyColumns: []string{"id"},
+	key: func(model models.Todo) []any {
+		return []any{model.Id}
+	},
+	fields: func(model *models.Todo) []any {
+		return []any{&model.Id, &model.Title, &model.UserId}
+	},
+}
+
+// TodoRepository runs queries on Todo table
+type TodoRepository struct {
+	client   *Client
+	includes []func(ctx context.Context, records []models.Todo) error
+}
+
+func (r *TodoRepository) include(load func(ctx context.Context, records []models.Todo) error) *TodoRepository {
+	return &TodoRepository{client: r.client, includes: append(slices.Clip(r.includes), load)}
+}
+
+// load fills relation fields of records that are included into the repository
+func (r *TodoRepository) load(ctx context.Context, records []models.Todo) error {
+	for _, load := range r.includes {
+		if err := load(ctx, records); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IncludeUser returns repository that loads User of the returned Todo records. Nested includes are applied to the loaded User records.
+func (r *TodoRepository) IncludeUser(nested ...func(repository *UserRepository) *UserRepository) *TodoRepository {
+	related := r.client.Users
+	for _, include := range nested {
+		related = include(related)
+	}
+	return r.include(func(ctx context.Context, records []models.Todo) error {
+		return loadRelation(ctx, r.client.db, userTable, "id", records,
+			func(model models.Todo) any { return model.UserId },
+			func(model models.User) any { return model.Id },
+			related.load,
+			func(model *models.Todo, loaded []models.User) {
+				if len(loaded) != 0 {
+					model.User = &loaded[0]
+				}
+			},
+		)
+	})
+}
+
+// IncludeNote returns repository that loads Note of the returned Todo records. Nested includes are applied to the loaded Note records.
+func (r *TodoRepository) IncludeNote(nested ...func(repository *NoteRepository) *NoteRepository) *TodoRepository {
+	related := r.client.Notes
+	for _, include := range nested {
+		related = include(related)
+	}
+	return r.include(func(ctx context.Context, records []models.Todo) error {
+		return loadRelation(ctx, r.client.db, noteTable, "id", records,
+			func(model models.Todo) any { return model.Id },
+			func(model models.Note) any { return model.Id },
+			related.load,
+			func(model *models.Todo, loaded []models.Note) {
+				if len(loaded) != 0 {
+					model.Note = &loaded[0]
+				}
+			},
+		)
+	})
+}
+
+// Create inserts new Todo and returns it with values generated by the database
+func (r *TodoRepository) Create(ctx context.Context, data TodoCreate) (models.Todo, error) {
+	var result models.Todo
+	err := r.client.Transaction(ctx, func(tx *Client) error {
+		var err error
+		result, err = tx.Todos.create(ctx, data, nil)
+		return err
+	})
+	if err != nil {
+		return result, err
+	}
+	return loadOne(ctx, result, r.load)
+}
+
+// create inserts data and related records, values replace columns of data (e.g. relation column of the nested create)
+func (r *TodoRepository) create(ctx context.Context, data TodoCreate, values []columnValue) (models.Todo, error) {
+	result, err := create(ctx, r.client.db, todoTable, withValues(data.values(), values))
+	if err != nil {
+		return result, err
+	}
+	if data.Note != nil {
+		created, err := r.client.Notes.create(ctx, *data.Note, []columnValue{{column: "id", value: result.Id}})
+		if err != nil {
+			return result, err
+		}
+		result.Note = &created
+	}
+	return result, nil
+}
+
+// FindUnique returns Todo selected by unique fields or nil if it doesn't exist
+func (r *TodoRepository) FindUnique(ctx context.Context, where TodoWhereUnique) (*models.Todo, error) {
+	result, err := findUnique(ctx, r.client.db, todoTable, where.condition())
+	if err != nil || result == nil {
+		return result, err
+	}
+	loaded, err := loadOne(ctx, *result, r.load)
+	return &loaded, err
+}
+
+// FindMany returns every Todo that matches args
+func (r *TodoRepository) FindMany(ctx context.Context, args TodoFindManyArgs) ([]models.Todo, error) {
+	result, err := findMany(ctx, r.client.db, todoTable, findManyQuery[models.Todo]{
+		where:   args.Where,
+		orderBy: args.OrderBy,
+		columns: args.Select,
+		take:    args.Take,
+		skip:    args.Skip,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, r.load(ctx, result)
+}
+
+// Update changes set fields of Todo selected by unique fields and returns updated Todo. ErrNotFound is returned if it doesn't exist.
+func (r *TodoRepository) Update(ctx context.Context, where TodoWhereUnique, data TodoUpdate) (models.Todo, error) {
+	result, err := update(ctx, r.client.db, todoTable, where.condition(), data.values())
+	if err != nil {
+		return result, err
+	}
+	return loadOne(ctx, result, r.load)
+}
+
+// Delete deletes Todo selected by unique fields and returns it. ErrNotFound is returned if it doesn't exist.
+func (r *TodoRepository) Delete(ctx context.Context, where TodoWhereUnique) (models.Todo, error) {
+	return remove(ctx, r.client.db, todoTable, where.condition())
+}
+
+// Upsert updates Todo selected by unique fields or creates it if it doesn't exist
+func (r *TodoRepository) Upsert(ctx context.Context, where TodoWhereUnique, createData TodoCreate, updateData TodoUpdate) (models.Todo, error) {
+	var result models.Todo
+	err := r.client.Transaction(ctx, func(tx *Client) error {
+		current, err := tx.Todos.FindUnique(ctx, where)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			result, err = tx.Todos.Create(ctx, createData)
+		} else {
+			result, err = tx.Todos.Update(ctx, where, updateData)
+		}
+		return err
+	})
+	if err != nil {
+		return result, err
+	}
+	return loadOne(ctx, result, r.load)
+}
+
+// Count returns number of Todo records that match every where condition
+func (r *TodoRepository) Count(ctx context.Context, where ...Where[models.Todo]) (int64, error) {
+	return count(ctx, r.client.db, todoTable, whereAll(where))
+}
@@ -0,0 +1,300 @@
+// Code generated by GoRelCli. DO NOT EDIT.
+// Schema hash: c84a19c22a205eb52d35f0561f869054b1373387b36e4391a482163ebca56280
+
+package client
+
+import (
+	"GoRelCli/gorel/enums"
+	"GoRelCli/gorel/models"
+	"context"
+	"slices"
+)
+
+// UserWhereUnique selects one User by unique fields, all set fields should match
+type UserWhereUnique struct {
+	Id    Field[int64]
+	Email Field[string]
+}
+
+func (w UserWhereUnique) condition() condition {
+	var conditions []condition
+	if value, isSet := w.Id.Get(); isSet {
+		conditions = append(conditions, equals("id", value))
+	}
+	if value, isSet := w.Email.Get(); isSet {
+		conditions = append(conditions, equals("email", value))
+	}
+	return and(conditions)
+}
+
+// UserWhereFields contains filters of User columns
+type UserWhereFields struct {
+	Id         OrderedFilter[models.User, int64]
+	Email      StringFilter[models.User]
+	Username   NullableStringFilter[models.User]
+	IsVerified Filter[models.User, bool]
+	UserType   Filter[models.User, enums.UserRole]
+}
+
+// UserWhere creates conditions for User, e.g. UserWhere.Id.Equals(value)
+var UserWhere = UserWhereFields{
+	Id:         newOrderedFilter[models.User, int64]("id"),
+	Email:      newStringFilter[models.User]("email"),
+	Username:   newNullableStringFilter[models.User]("username"),
+	IsVerified: newFilter[models.User, bool]("isVerified"),
+	UserType:   newFilter[models.User, enums.UserRole]("userType"),
+}
+
+// UserColumns contains columns of User
+type UserColumns struct {
+	Id         Column[models.User]
+	Email      Column[models.User]
+	Username   Column[models.User]
+	IsVerified Column[models.User]
+	UserType   Column[models.User]
+}
+
+// UserSelect contains columns that can be selected by FindMany
+var UserSelect = UserColumns{
+	Id:         Column[models.User]{name: "id"},
+	Email:      Column[models.User]{name: "email"},
+	Username:   Column[models.User]{name: "username"},
+	IsVerified: Column[models.User]{name: "isVerified"},
+	UserType:   Column[models.User]{name: "userType"},
+}
+
+// UserOrderBy contains columns that can be used to order User records, e.g. UserOrderBy.Id.Desc()
+var UserOrderBy = UserSelect
+
+// UserCreate contains values of the new User. Fields with default values and nullable fields can be omitted.
+// Related records are created in the same transaction, their relation fields are set to the created User.
+type UserCreate struct {
+	Id         Field[int64]
+	Email      string
+	Username   Field[*string]
+	IsVerified Field[bool]
+	UserType   enums.UserRole
+	Todos      []TodoCreate
+	Videos     []UserToVideoRelationCreate
+}
+
+func (d UserCreate) values() []columnValue {
+	var values []columnValue
+	if value, isSet := d.Id.Get(); isSet {
+		values = append(values, columnValue{column: "id", value: value})
+	}
+	values = append(values, columnValue{column: "email", value: d.Email})
+	if value, isSet := d.Username.Get(); isSet {
+		values = append(values, columnValue{column: "username", value: value})
+	}
+	if value, isSet := d.IsVerified.Get(); isSet {
+		values = append(values, columnValue{column: "isVerified", value: value})
+	}
+	values = append(values, columnValue{column: "userType", value: d.UserType})
+	return values
+}
+
+// UserUpdate contains values that should be changed, only set fields are updated
+type UserUpdate struct {
+	Id         Field[int64]
+	Email      Field[string]
+	Username   Field[*string]
+	IsVerified Field[bool]
+	UserType   Field[enums.UserRole]
+}
+
+func (d UserUpdate) values() []columnValue {
+	var values []columnValue
+	if value, isSet := d.Id.Get(); isSet {
+		values = append(values, columnValue{column: "id", value: value})
+	}
+	if value, isSet := d.Email.Get(); isSet {
+		values = append(values, columnValue{column: "email", value: value})
+	}
+	if value, isSet := d.Username.Get(); isSet {
+		values = append(values, columnValue{column: "username", value: value})
+	}
+	if value, isSet := d.IsVerified.Get(); isSet {
+		values = append(values, columnValue{column: "isVerified", value: value})
+	}
+	if value, isSet := d.UserType.Get(); isSet {
+		values = append(values, columnValue{column: "userType", value: value})
+	}
+	return values
+}
+
+// UserFindManyArgs contains options of UserRepository.FindMany.
+// Records should match every Where condition, Select limits loaded columns (all columns are loaded by default).
+type UserFindManyArgs struct {
+	Where   []Where[models.User]
+	OrderBy []OrderBy[models.User]
+	Select  []Column[models.User]
+	Take    int
+	Skip    int
+}
+
+var userTable = table[models.User]{
+	name:       "User",
+	columns:    []string{"id", "email", "username", "isVerified", "userType"},
+	keyColumns: []string{"id"},
+	key: func(model models.User) []any {
+		return []any{model.Id}
+	},
+	fields: func(model *models.User) []any {
+		return []any{&model.Id, &model.Email, &model.Username, &model.IsVerified, &model.UserType}
+	},
+}
+
+// UserRepository runs queries on User table
+type UserRepository struct {
+	client   *Client
+	includes []func(ctx context.Context, records []models.User) error
+}
+
+func (r *UserRepository) include(load func(ctx context.Context, records []models.User) error) *UserRepository {
+	return &UserRepository{client: r.client, includes: append(slices.Clip(r.includes), load)}
+}
+
+// load fills relation fields of records that are included into the repository
+func (r *UserRepository) load(ctx context.Context, records []models.User) error {
+	for _, load := range r.includes {
+		if err := load(ctx, records); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IncludeTodos returns repository that loads Todos of the returned User records. Nested includes are applied to the loaded Todo records.
+func (r *UserRepository) IncludeTodos(nested ...func(repository *TodoRepository) *TodoRepository) *UserRepository {
+	related := r.client.Todos
+	for _, include := range nested {
+		related = include(related)
+	}
+	return r.include(func(ctx context.Context, records []models.User) error {
+		return loadRelation(ctx, r.client.db, todoTable, "userId", records,
+			func(model models.User) any { return model.Id },
+			func(model models.Todo) any { return model.UserId },
+			related.load,
+			func(model *models.User, loaded []models.Todo) { model.Todos = loaded },
+		)
+	})
+}
+
+// IncludeVideos returns repository that loads Videos of the returned User records. Nested includes are applied to the loaded UserToVideoRelation records.
+func (r *UserRepository) IncludeVideos(nested ...func(repository *UserToVideoRelationRepository) *UserToVideoRelationRepository) *UserRepository {
+	related := r.client.UserToVideoRelations
+	for _, include := range nested {
+		related = include(related)
+	}
+	return r.include(func(ctx context.Context, records []models.User) error {
+		return loadRelation(ctx, r.client.db, userToVideoRelationTable, "userId", records,
+			func(model models.User) any { return model.Id },
+			func(model models.UserToVideoRelation) any { return model.UserId },
+			related.load,
+			func(model *models.User, loaded []models.UserToVideoRelation) { model.Videos = loaded },
+		)
+	})
+}
+
+// Create inserts new User and returns it with values generated by the database
+func (r *UserRepository) Create(ctx context.Context, data UserCreate) (models.User, error) {
+	var result models.User
+	err := r.client.Transaction(ctx, func(tx *Client) error {
+		var err error
+		result, err = tx.Users.create(ctx, data, nil)
+		return err
+	})
+	if err != nil {
+		return result, err
+	}
+	return loadOne(ctx, result, r.load)
+}
+
+// create inserts data and related records, values replace columns of data (e.g. relation column of the nested create)
+func (r *UserRepository) create(ctx context.Context, data UserCreate, values []columnValue) (models.User, error) {
+	result, err := create(ctx, r.client.db, userTable, withValues(data.values(), values))
+	if err != nil {
+		return result, err
+	}
+	for _, nested := range data.Todos {
+		created, err := r.client.Todos.create(ctx, nested, []columnValue{{column: "userId", value: result.Id}})
+		if err != nil {
+			return result, err
+		}
+		result.Todos = append(result.Todos, created)
+	}
+	for _, nested := range data.Videos {
+		created, err := r.client.UserToVideoRelations.create(ctx, nested, []columnValue{{column: "userId", value: result.Id}})
+		if err != nil {
+			return result, err
+		}
+		result.Videos = append(result.Videos, created)
+	}
+	return result, nil
+}
+
+// FindUnique returns User selected by unique fields or nil if it doesn't exist
+func (r *UserRepository) FindUnique(ctx context.Context, where UserWhereUnique) (*models.User, error) {
+	result, err := findUnique(ctx, r.client.db, userTable, where.condition())
+	if err != nil || result == nil {
+		return result, err
+	}
+	loaded, err := loadOne(ctx, *result, r.load)
+	return &loaded, err
+}
+
+// FindMany returns every User that matches args
+func (r *UserRepository) FindMany(ctx context.Context, args UserFindManyArgs) ([]models.User, error) {
+	result, err := findMany(ctx, r.client.db, userTable, findManyQuery[models.User]{
+		where:   args.Where,
+		orderBy: args.OrderBy,
+		columns: args.Select,
+		take:    args.Take,
+		skip:    args.Skip,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, r.load(ctx, result)
+}
+
+// Update changes set fields of User selected by unique fields and returns updated User. ErrNotFound is returned if it doesn't exist.
+func (r *UserRepository) Update(ctx context.Context, where UserWhereUnique, data UserUpdate) (models.User, error) {
+	result, err := update(ctx, r.client.db, userTable, where.condition(), data.values())
+	if err != nil {
+		return result, err
+	}
+	return loadOne(ctx, result, r.load)
+}
+
+// Delete deletes User selected by unique fields and returns it. ErrNotFound is returned if it doesn't exist.
+func (r *UserRepository) Delete(ctx context.Context, where UserWhereUnique) (models.User, error) {
+	return remove(ctx, r.client.db, userTable, where.condition())
+}
+
+// Upsert updates User selected by unique fields or creates it if it doesn't exist
+func (r *UserRepository) Upsert(ctx context.Context, where UserWhereUnique, createData UserCreate, updateData UserUpdate) (models.User, error) {
+	var result models.User
+	err := r.client.Transaction(ctx, func(tx *Client) error {
+		current, err := tx.Users.FindUnique(ctx, where)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			result, err = tx.Users.Create(ctx, createData)
+		} else {
+			result, err = tx.Users.Update(ctx, where, updateData)
+		}
+		return err
+	})
+	if err != nil {
+		return result, err
+	}
+	return loadOne(ctx, result, r.load)
+}
+
+// Count returns number of User records that match every where condition
+func (r *UserRepository) Count(ctx context.Context, where ...Where[models.User]) (int64, error) {
+	return count(ctx, r.client.db, userTable, whereAll(where))
+}
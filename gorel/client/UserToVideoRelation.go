@@ -0,0 +1,257 @@
+// Code generated by GoRelCli. DO NOT EDIT.
+// Schema hash: c84a19c22a205eb52d35f0561f869054b1373387b36e4391a482163ebca56280
+
+package client
+
+import (
+	"GoRelCli/gorel/models"
+	"context"
+	"slices"
+)
+
+// UserToVideoRelationWhereUnique selects one UserToVideoRelation by unique fields, all set fields should match
+type UserToVideoRelationWhereUnique struct {
+	Id Field[int64]
+}
+
+func (w UserToVideoRelationWhereUnique) condition() condition {
+	var conditions []condition
+	if value, isSet := w.Id.Get(); isSet {
+		conditions = append(conditions, equals("id", value))
+	}
+	return and(conditions)
+}
+
+// UserToVideoRelationWhereFields contains filters of UserToVideoRelation columns
+type UserToVideoRelationWhereFields struct {
+	Id      OrderedFilter[models.UserToVideoRelation, int64]
+	UserId  OrderedFilter[models.UserToVideoRelation, int64]
+	VideoId OrderedFilter[models.UserToVideoRelation, int64]
+}
+
+// UserToVideoRelationWhere creates conditions for UserToVideoRelation, e.g. UserToVideoRelationWhere.Id.Equals(value)
+var UserToVideoRelationWhere = UserToVideoRelationWhereFields{
+	Id:      newOrderedFilter[models.UserToVideoRelation, int64]("id"),
+	UserId:  newOrderedFilter[models.UserToVideoRelation, int64]("userId"),
+	VideoId: newOrderedFilter[models.UserToVideoRelation, int64]("videoId"),
+}
+
+// UserToVideoRelationColumns contains columns of UserToVideoRelation
+type UserToVideoRelationColumns struct {
+	Id      Column[models.UserToVideoRelation]
+	UserId  Column[models.UserToVideoRelation]
+	VideoId Column[models.UserToVideoRelation]
+}
+
+// UserToVideoRelationSelect contains columns that can be selected by FindMany
+var UserToVideoRelationSelect = UserToVideoRelationColumns{
+	Id:      Column[models.UserToVideoRelation]{name: "id"},
+	UserId:  Column[models.UserToVideoRelation]{name: "userId"},
+	VideoId: Column[models.UserToVideoRelation]{name: "videoId"},
+}
+
+// UserToVideoRelationOrderBy contains columns that can be used to order UserToVideoRelation records, e.g. UserToVideoRelationOrderBy.Id.Desc()
+var UserToVideoRelationOrderBy = UserToVideoRelationSelect
+
+// UserToVideoRelationCreate contains values of the new UserToVideoRelation. Fields with default values and nullable fields can be omitted.
+type UserToVideoRelationCreate struct {
+	Id      Field[int64]
+	UserId  int64
+	VideoId int64
+}
+
+func (d UserToVideoRelationCreate) values() []columnValue {
+	var values []columnValue
+	if value, isSet := d.Id.Get(); isSet {
+		values = append(values, columnValue{column: "id", value: value})
+	}
+	values = append(values, columnValue{column: "userId", value: d.UserId})
+	values = append(values, columnValue{column: "videoId", value: d.VideoId})
+	return values
+}
+
+// UserToVideoRelationUpdate contains values that should be changed, only set fields are updated
+type UserToVideoRelationUpdate struct {
+	Id      Field[int64]
+	UserId  Field[int64]
+	VideoId Field[int64]
+}
+
+func (d UserToVideoRelationUpdate) values() []columnValue {
+	var values []columnValue
+	if value, isSet := d.Id.Get(); isSet {
+		values = append(values, columnValue{column: "id", value: value})
+	}
+	if value, isSet := d.UserId.Get(); isSet {
+		values = append(values, columnValue{column: "userId", value: value})
+	}
+	if value, isSet := d.VideoId.Get(); isSet {
+		values = append(values, columnValue{column: "videoId", value: value})
+	}
+	return values
+}
+
+// UserToVideoRelationFindManyArgs contains options of UserToVideoRelationRepository.FindMany.
+// Records should match every Where condition, Select limits loaded columns (all columns are loaded by default).
+type UserToVideoRelationFindManyArgs struct {
+	Where   []Where[models.UserToVideoRelation]
+	OrderBy []OrderBy[models.UserToVideoRelation]
+	Select  []Column[models.UserToVideoRelation]
+	Take    int
+	Skip    int
+}
+
+var userToVideoRelationTable = table[models.UserToVideoRelation]{
+	name:       "UserToVideoRelation",
+	columns:    []string{"id", "userId", "videoId"},
+	keyColumns: []string{"id"},
+	key: func(model models.UserToVideoRelation) []any {
+		return []any{model.Id}
+	},
+	fields: func(model *models.UserToVideoRelation) []any {
+		return []any{&model.Id, &model.UserId, &model.VideoId}
+	},
+}
+
+// UserToVideoRelationRepository runs queries on UserToVideoRelation table
+type UserToVideoRelationRepository struct {
+	client   *Client
+	includes []func(ctx context.Context, records []models.UserToVideoRelation) error
+}
+
+func (r *UserToVideoRelationRepository) include(load func(ctx context.Context, records []models.UserToVideoRelation) error) *UserToVideoRelationRepository {
+	return &UserToVideoRelationRepository{client: r.client, includes: append(slices.Clip(r.includes), load)}
+}
+
+// load fills relation fields of records that are included into the repository
+func (r *UserToVideoRelationRepository) load(ctx context.Context, records []models.UserToVideoRelation) error {
+	for _, load := range r.includes {
+		if err := load(ctx, records); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IncludeUser returns repository that loads User of the returned UserToVideoRelation records. Nested includes are applied to the loaded User records.
+func (r *UserToVideoRelationRepository) IncludeUser(nested ...func(repository *UserRepository) *UserRepository) *UserToVideoRelationRepository {
+	related := r.client.Users
+	for _, include := range nested {
+		related = include(related)
+	}
+	return r.include(func(ctx context.Context, records []models.UserToVideoRelation) error {
+		return loadRelation(ctx, r.client.db, userTable, "id", records,
+			func(model models.UserToVideoRelation) any { return model.UserId },
+			func(model models.User) any { return model.Id },
+			related.load,
+			func(model *models.UserToVideoRelation, loaded []models.User) {
+				if len(loaded) != 0 {
+					model.User = &loaded[0]
+				}
+			},
+		)
+	})
+}
+
+// IncludeVideo returns repository that loads Video of the returned UserToVideoRelation records. Nested includes are applied to the loaded Video records.
+func (r *UserToVideoRelationRepository) IncludeVideo(nested ...func(repository *VideoRepository) *VideoRepository) *UserToVideoRelationRepository {
+	related := r.client.Videos
+	for _, include := range nested {
+		related = include(related)
+	}
+	return r.include(func(ctx context.Context, records []models.UserToVideoRelation) error {
+		return loadRelation(ctx, r.client.db, videoTable, "id", records,
+			func(model models.UserToVideoRelation) any { return model.VideoId },
+			func(model models.Video) any { return model.Id },
+			related.load,
+			func(model *models.UserToVideoRelation, loaded []models.Video) {
+				if len(loaded) != 0 {
+					model.Video = &loaded[0]
+				}
+			},
+		)
+	})
+}
+
+// Create inserts new UserToVideoRelation and returns it with values generated by the database
+func (r *UserToVideoRelationRepository) Create(ctx context.Context, data UserToVideoRelationCreate) (models.UserToVideoRelation, error) {
+	result, err := r.create(ctx, data, nil)
+	if err != nil {
+		return result, err
+	}
+	return loadOne(ctx, result, r.load)
+}
+
+// create inserts data and related records, values replace columns of data (e.g. relation column of the nested create)
+func (r *UserToVideoRelationRepository) create(ctx context.Context, data UserToVideoRelationCreate, values []columnValue) (models.UserToVideoRelation, error) {
+	result, err := create(ctx, r.client.db, userToVideoRelationTable, withValues(data.values(), values))
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// FindUnique returns UserToVideoRelation selected by unique fields or nil if it doesn't exist
+func (r *UserToVideoRelationRepository) FindUnique(ctx context.Context, where UserToVideoRelationWhereUnique) (*models.UserToVideoRelation, error) {
+	result, err := findUnique(ctx, r.client.db, userToVideoRelationTable, where.condition())
+	if err != nil || result == nil {
+		return result, err
+	}
+	loaded, err := loadOne(ctx, *result, r.load)
+	return &loaded, err
+}
+
+// FindMany returns every UserToVideoRelation that matches args
+func (r *UserToVideoRelationRepository) FindMany(ctx context.Context, args UserToVideoRelationFindManyArgs) ([]models.UserToVideoRelation, error) {
+	result, err := findMany(ctx, r.client.db, userToVideoRelationTable, findManyQuery[models.UserToVideoRelation]{
+		where:   args.Where,
+		orderBy: args.OrderBy,
+		columns: args.Select,
+		take:    args.Take,
+		skip:    args.Skip,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, r.load(ctx, result)
+}
+
+// Update changes set fields of UserToVideoRelation selected by unique fields and returns updated UserToVideoRelation. ErrNotFound is returned if it doesn't exist.
+func (r *UserToVideoRelationRepository) Update(ctx context.Context, where UserToVideoRelationWhereUnique, data UserToVideoRelationUpdate) (models.UserToVideoRelation, error) {
+	result, err := update(ctx, r.client.db, userToVideoRelationTable, where.condition(), data.values())
+	if err != nil {
+		return result, err
+	}
+	return loadOne(ctx, result, r.load)
+}
+
+// Delete deletes UserToVideoRelation selected by unique fields and returns it. ErrNotFound is returned if it doesn't exist.
+func (r *UserToVideoRelationRepository) Delete(ctx context.Context, where UserToVideoRelationWhereUnique) (models.UserToVideoRelation, error) {
+	return remove(ctx, r.client.db, userToVideoRelationTable, where.condition())
+}
+
+// Upsert updates UserToVideoRelation selected by unique fields or creates it if it doesn't exist
+func (r *UserToVideoRelationRepository) Upsert(ctx context.Context, where UserToVideoRelationWhereUnique, createData UserToVideoRelationCreate, updateData UserToVideoRelationUpdate) (models.UserToVideoRelation, error) {
+	var result models.UserToVideoRelation
+	err := r.client.Transaction(ctx, func(tx *Client) error {
+		current, err := tx.UserToVideoRelations.FindUnique(ctx, where)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			result, err = tx.UserToVideoRelations.Create(ctx, createData)
+		} else {
+			result, err = tx.UserToVideoRelations.Update(ctx, where, updateData)
+		}
+		return err
+	})
+	if err != nil {
+		return result, err
+	}
+	return loadOne(ctx, result, r.load)
+}
+
+// Count returns number of UserToVideoRelation records that match every where condition
+func (r *UserToVideoRelationRepository) Count(ctx context.Context, where ...Where[models.UserToVideoRelation]) (int64, error) {
+	return count(ctx, r.client.db, userToVideoRelationTable, whereAll(where))
+}
@@ -0,0 +1,237 @@
+// Code generated by GoRelCli. DO NOT EDIT.
+// Schema hash: c84a19c22a205eb52d35f0561f869054b1373387b36e4391a482163ebca56280
+
+package client
+
+import (
+	"GoRelCli/gorel/models"
+	"context"
+	"slices"
+)
+
+// VideoWhereUnique selects one Video by unique fields, all set fields should match
+type VideoWhereUnique struct {
+	Id Field[int64]
+}
+
+func (w VideoWhereUnique) condition() condition {
+	var conditions []condition
+	if value, isSet := w.Id.Get(); isSet {
+		conditions = append(conditions, equals("id", value))
+	}
+	return and(conditions)
+}
+
+// VideoWhereFields contains filters of Video columns
+type VideoWhereFields struct {
+	Id    OrderedFilter[models.Video, int64]
+	Title StringFilter[models.Video]
+}
+
+// VideoWhere creates conditions for Video, e.g. VideoWhere.Id.Equals(value)
+var VideoWhere = VideoWhereFields{
+	Id:    newOrderedFilter[models.Video, int64]("id"),
+	Title: newStringFilter[models.Video]("title"),
+}
+
+// VideoColumns contains columns of Video
+type VideoColumns struct {
+	Id    Column[models.Video]
+	Title Column[models.Video]
+}
+
+// VideoSelect contains columns that can be selected by FindMany
+var VideoSelect = VideoColumns{
+	Id:    Column[models.Video]{name: "id"},
+	Title: Column[models.Video]{name: "title"},
+}
+
+// VideoOrderBy contains columns that can be used to order Video records, e.g. VideoOrderBy.Id.Desc()
+var VideoOrderBy = VideoSelect
+
+// VideoCreate contains values of the new Video. Fields with default values and nullable fields can be omitted.
+// Related records are created in the same transaction, their relation fields are set to the created Video.
+type VideoCreate struct {
+	Id    Field[int64]
+	Title string
+	Users []UserToVideoRelationCreate
+}
+
+func (d VideoCreate) values() []columnValue {
+	var values []columnValue
+	if value, isSet := d.Id.Get(); isSet {
+		values = append(values, columnValue{column: "id", value: value})
+	}
+	values = append(values, columnValue{column: "title", value: d.Title})
+	return values
+}
+
+// VideoUpdate contains values that should be changed, only set fields are updated
+type VideoUpdate struct {
+	Id    Field[int64]
+	Title Field[string]
+}
+
+func (d VideoUpdate) values() []columnValue {
+	var values []columnValue
+	if value, isSet := d.Id.Get(); isSet {
+		values = append(values, columnValue{column: "id", value: value})
+	}
+	if value, isSet := d.Title.Get(); isSet {
+		values = append(values, columnValue{column: "title", value: value})
+	}
+	return values
+}
+
+// VideoFindManyArgs contains options of VideoRepository.FindMany.
+// Records should match every Where condition, Select limits loaded columns (all columns are loaded by default).
+type VideoFindManyArgs struct {
+	Where   []Where[models.Video]
+	OrderBy []OrderBy[models.Video]
+	Select  []Column[models.Video]
+	Take    int
+	Skip    int
+}
+
+var videoTable = table[models.Video]{
+	name:       "Video",
+	columns:    []string{"id", "title"},
+	keyColumns: []string{"id"},
+	key: func(model models.Video) []any {
+		return []any{model.Id}
+	},
+	fields: func(model *models.Video) []any {
+		return []any{&model.Id, &model.Title}
+	},
+}
+
+// VideoRepository runs queries on Video table
+type VideoRepository struct {
+	client   *Client
+	includes []func(ctx context.Context, records []models.Video) error
+}
+
+func (r *VideoRepository) include(load func(ctx context.Context, records []models.Video) error) *VideoRepository {
+	return &VideoRepository{client: r.client, includes: append(slices.Clip(r.includes), load)}
+}
+
+// load fills relation fields of records that are included into the repository
+func (r *VideoRepository) load(ctx context.Context, records []models.Video) error {
+	for _, load := range r.includes {
+		if err := load(ctx, records); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IncludeUsers returns repository that loads Users of the returned Video records. Nested includes are applied to the loaded UserToVideoRelation records.
+func (r *VideoRepository) IncludeUsers(nested ...func(repository *UserToVideoRelationRepository) *UserToVideoRelationRepository) *VideoRepository {
+	related := r.client.UserToVideoRelations
+	for _, include := range nested {
+		related = include(related)
+	}
+	return r.include(func(ctx context.Context, records []models.Video) error {
+		return loadRelation(ctx, r.client.db, userToVideoRelationTable, "videoId", records,
+			func(model models.Video) any { return model.Id },
+			func(model models.UserToVideoRelation) any { return model.VideoId },
+			related.load,
+			func(model *models.Video, loaded []models.UserToVideoRelation) { model.Users = loaded },
+		)
+	})
+}
+
+// Create inserts new Video and returns it with values generated by the database
+func (r *VideoRepository) Create(ctx context.Context, data VideoCreate) (models.Video, error) {
+	var result models.Video
+	err := r.client.Transaction(ctx, func(tx *Client) error {
+		var err error
+		result, err = tx.Videos.create(ctx, data, nil)
+		return err
+	})
+	if err != nil {
+		return result, err
+	}
+	return loadOne(ctx, result, r.load)
+}
+
+// create inserts data and related records, values replace columns of data (e.g. relation column of the nested create)
+func (r *VideoRepository) create(ctx context.Context, data VideoCreate, values []columnValue) (models.Video, error) {
+	result, err := create(ctx, r.client.db, videoTable, withValues(data.values(), values))
+	if err != nil {
+		return result, err
+	}
+	for _, nested := range data.Users {
+		created, err := r.client.UserToVideoRelations.create(ctx, nested, []columnValue{{column: "videoId", value: result.Id}})
+		if err != nil {
+			return result, err
+		}
+		result.Users = append(result.Users, created)
+	}
+	return result, nil
+}
+
+// FindUnique returns Video selected by unique fields or nil if it doesn't exist
+func (r *VideoRepository) FindUnique(ctx context.Context, where VideoWhereUnique) (*models.Video, error) {
+	result, err := findUnique(ctx, r.client.db, videoTable, where.condition())
+	if err != nil || result == nil {
+		return result, err
+	}
+	loaded, err := loadOne(ctx, *result, r.load)
+	return &loaded, err
+}
+
+// FindMany returns every Video that matches args
+func (r *VideoRepository) FindMany(ctx context.Context, args VideoFindManyArgs) ([]models.Video, error) {
+	result, err := findMany(ctx, r.client.db, videoTable, findManyQuery[models.Video]{
+		where:   args.Where,
+		orderBy: args.OrderBy,
+		columns: args.Select,
+		take:    args.Take,
+		skip:    args.Skip,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, r.load(ctx, result)
+}
+
+// Update changes set fields of Video selected by unique fields and returns updated Video. ErrNotFound is returned if it doesn't exist.
+func (r *VideoRepository) Update(ctx context.Context, where VideoWhereUnique, data VideoUpdate) (models.Video, error) {
+	result, err := update(ctx, r.client.db, videoTable, where.condition(), data.values())
+	if err != nil {
+		return result, err
+	}
+	return loadOne(ctx, result, r.load)
+}
+
+// Delete deletes Video selected by unique fields and returns it. ErrNotFound is returned if it doesn't exist.
+func (r *VideoRepository) Delete(ctx context.Context, where VideoWhereUnique) (models.Video, error) {
+	return remove(ctx, r.client.db, videoTable, where.condition())
+}
+
+// Upsert updates Video selected by unique fields or creates it if it doesn't exist
+func (r *VideoRepository) Upsert(ctx context.Context, where VideoWhereUnique, createData VideoCreate, updateData VideoUpdate) (models.Video, error) {
+	var result models.Video
+	err := r.client.Transaction(ctx, func(tx *Client) error {
+		current, err := tx.Videos.FindUnique(ctx, where)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			result, err = tx.Videos.Create(ctx, createData)
+		} else {
+			result, err = tx.Videos.Update(ctx, where, updateData)
+		}
+		return err
+	})
+	if err != nil {
+		return result, err
+	}
+	return loadOne(ctx, result, r.load)
+}
+
+// Count returns number of Video records that match every where condition
+func (r *VideoRepository) Count(ctx context.Context, where ...Where[models.Video]) (int64, error) {
+	return count(ctx, r.client.db, videoTable, whereAll(where))
+}
@@ -1,8 +1,91 @@
+// Code generated by GoRelCli. DO NOT EDIT.
+// Schema hash: c84a19c22a205eb52d35f0561f869054b1373387b36e4391a482163ebca56280
+
 package enums
 
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+)
+
 type UserRole string
 
 const (
-	Admin UserRole = "Admin"
-	User           = "User"
+	UserRoleAdmin UserRole = "Admin"
+	UserRoleUser  UserRole = "User"
 )
+
+var userRoleValues = []UserRole{UserRoleAdmin, UserRoleUser}
+
+// Values returns every value of UserRole in the order of the schema
+func (e UserRole) Values() []UserRole {
+	return append([]UserRole(nil), userRoleValues...)
+}
+
+// IsValid checks if e is one of the UserRole values
+func (e UserRole) IsValid() bool {
+	switch e {
+	case UserRoleAdmin, UserRoleUser:
+		return true
+	}
+	return false
+}
+
+func (e UserRole) String() string {
+	return string(e)
+}
+
+// ParseUserRole converts value to UserRole, error is returned for unknown values
+func ParseUserRole(value string) (UserRole, error) {
+	if e := UserRole(value); e.IsValid() {
+		return e, nil
+	}
+	return "", errors.New(fmt.Sprintf("%q is not a valid UserRole", value))
+}
+
+// Scan implements sql.Scanner
+func (e *UserRole) Scan(src any) error {
+	var value string
+	switch typed := src.(type) {
+	case string:
+		value = typed
+	case []byte:
+		value = string(typed)
+	default:
+		return errors.New(fmt.Sprintf("can't scan %T into UserRole", src))
+	}
+
+	parsed, err := ParseUserRole(value)
+	if err != nil {
+		return err
+	}
+	*e = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, unknown values are not written to the database
+func (e UserRole) Value() (driver.Value, error) {
+	if !e.IsValid() {
+		return nil, errors.New(fmt.Sprintf("%q is not a valid UserRole", string(e)))
+	}
+	return string(e), nil
+}
+
+// MarshalText implements encoding.TextMarshaler, it is also used by encoding/json
+func (e UserRole) MarshalText() ([]byte, error) {
+	if !e.IsValid() {
+		return nil, errors.New(fmt.Sprintf("%q is not a valid UserRole", string(e)))
+	}
+	return []byte(e), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, it is also used by encoding/json
+func (e *UserRole) UnmarshalText(text []byte) error {
+	parsed, err := ParseUserRole(string(text))
+	if err != nil {
+		return err
+	}
+	*e = parsed
+	return nil
+}
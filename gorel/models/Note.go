@@ -0,0 +1,10 @@
+// Code generated by GoRelCli. DO NOT EDIT.
+// Schema hash: c84a19c22a205eb52d35f0561f869054b1373387b36e4391a482163ebca56280
+
+package models
+
+type Note struct {
+	Id   string `gorel:"id"`
+	Text string `gorel:"text"`
+	Todo *Todo  `gorel:"todo,relation"`
+}
@@ -1,8 +1,12 @@
-package models
+// Code generated by GoRelCli. DO NOT EDIT.
+// Schema hash: c84a19c22a205eb52d35f0561f869054b1373387b36e4391a482163ebca56280
 
+package models
 
-type Todo struct{
-	Id string `gorel:"id"`
-	Title string `gorel:"title"`
-	Userid int64 `gorel:"userId"`
-}
\ No newline at end of file
+type Todo struct {
+	Id     string `gorel:"id"`
+	Title  string `gorel:"title"`
+	UserId int64  `gorel:"userId"`
+	User   *User  `gorel:"user,relation"`
+	Note   *Note  `gorel:"note,relation"`
+}
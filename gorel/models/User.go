@@ -1,12 +1,18 @@
+// Code generated by GoRelCli. DO NOT EDIT.
+// Schema hash: c84a19c22a205eb52d35f0561f869054b1373387b36e4391a482163ebca56280
+
 package models
 
-import "GoRelCli/gorel/enums"
+import (
+	"GoRelCli/gorel/enums"
+)
 
 type User struct {
-	Id         int64          `gorel:"id"`
-	Email      string         `gorel:"email"`
-	Username   string         `gorel:"username"`
-	Isverified bool           `gorel:"isVerified"`
-	Usertype   enums.UserRole `gorel:"userType"`
-	Todos      []Todo         `gorel:"todos"`
+	Id         int64                 `gorel:"id"`
+	Email      string                `gorel:"email"`
+	Username   *string               `gorel:"username"`
+	IsVerified bool                  `gorel:"isVerified"`
+	UserType   enums.UserRole        `gorel:"userType"`
+	Todos      []Todo                `gorel:"todos,relation"`
+	Videos     []UserToVideoRelation `gorel:"videos,relation"`
 }
@@ -0,0 +1,12 @@
+// Code generated by GoRelCli. DO NOT EDIT.
+// Schema hash: c84a19c22a205eb52d35f0561f869054b1373387b36e4391a482163ebca56280
+
+package models
+
+type UserToVideoRelation struct {
+	Id      int64  `gorel:"id"`
+	UserId  int64  `gorel:"userId"`
+	VideoId int64  `gorel:"videoId"`
+	User    *User  `gorel:"user,relation"`
+	Video   *Video `gorel:"video,relation"`
+}
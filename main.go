@@ -4,6 +4,8 @@ import (
 	"GoRelCli/clean"
 	"GoRelCli/generate"
 	"GoRelCli/migrate"
+	"GoRelCli/models/flag_model"
+	"GoRelCli/pull"
 	"flag"
 	"fmt"
 	"os"
@@ -11,20 +13,85 @@ import (
 
 func listAvailableCommands() {
 	fmt.Println("List of available subcommands")
-	fmt.Println("\t- migrate (runs migrations with options provided in gorel_schema.yml)")
+	fmt.Println("\t- migrate (runs migrations with options provided in gorel_schema.yml, same as migrate dev)")
+	fmt.Println("\t- migrate dev (applies pending migrations, creates new migration file from schema changes and applies it)")
+	fmt.Println("\t- migrate deploy (applies pending migration files without generating new ones)")
+	fmt.Println("\t- migrate status (lists applied, pending and modified migrations, fails if database is behind)")
+	fmt.Println("\t- migrate rollback (reverts last N applied migrations, use --steps to specify N)")
+	fmt.Println("\t- migrate diff (prints sql required to sync database with schema without running it)")
 	fmt.Println("\t- generate (generates go structs with options provided in gorel_schema.yml)")
 	fmt.Println("\t- clean (cleans names inside gorel_schema.yml)")
+	fmt.Println("\t- pull (creates models and enums inside gorel_schema.yml from existing database)")
 }
 
-func getFlags(args []string) (path string, output string) {
+func getFlags(args []string) flag_model.Flags {
 	fs := flag.FlagSet{}
 	pathPtr := fs.String("path", "", "Path to GoRelCli schema file")
 	outputPtr := fs.String("project_path", "", "Path to folder where generated files will be located")
+	namePtr := fs.String("name", "", "Name of the migration")
+	stepsPtr := fs.Int("steps", 1, "Number of migrations to rollback")
+	envFilePtr := fs.String("env-file", "", "Path to .env file (GOREL_ENV_FILE env variable can be used instead)")
+	var yes bool
+	fs.BoolVar(&yes, "yes", false, "Apply destructive changes without asking for permission")
+	fs.BoolVar(&yes, "force", false, "Same as --yes")
+	dryRunPtr := fs.Bool("dry-run", false, "Print sql of the migration without changing the database")
+	outputFilePtr := fs.String("output", "", "Path to file where sql will be written (stdout by default)")
+	fromEmptyPtr := fs.Bool("from-empty", false, "Generate sql for an empty database without connecting to it")
 	if err := fs.Parse(args); err != nil {
 		fmt.Println("Error while parsing flags")
 		os.Exit(3)
 	}
-	return *pathPtr, *outputPtr
+	return flag_model.Flags{
+		Path:        *pathPtr,
+		ProjectPath: *outputPtr,
+		Name:        *namePtr,
+		Steps:       *stepsPtr,
+		Yes:         yes,
+		EnvFile:     *envFilePtr,
+		DryRun:      *dryRunPtr,
+		Output:      *outputFilePtr,
+		FromEmpty:   *fromEmptyPtr,
+	}
+}
+
+func getSubcommand(args []string) (subcommand string, flagArgs []string) {
+	if len(args) == 0 || args[0][0:1] == "-" {
+		return "", args
+	}
+	return args[0], args[1:]
+}
+
+func getMigrateHandler(args []string) func() error {
+	subcommand, flagArgs := getSubcommand(args)
+	flags := getFlags(flagArgs)
+
+	switch subcommand {
+	case "", "dev":
+		return func() error {
+			return migrate.Dev(flags)
+		}
+	case "deploy":
+		return func() error {
+			return migrate.Deploy(flags)
+		}
+	case "status":
+		return func() error {
+			return migrate.Status(flags)
+		}
+	case "rollback":
+		return func() error {
+			return migrate.Rollback(flags)
+		}
+	case "diff":
+		return func() error {
+			return migrate.Diff(flags)
+		}
+	default:
+		fmt.Println(fmt.Sprintf("Command with name 'migrate %s' not found", subcommand))
+		listAvailableCommands()
+		os.Exit(3)
+	}
+	return func() error { return nil }
 }
 
 func getHandler(args []string) func() error {
@@ -40,20 +107,24 @@ func getHandler(args []string) func() error {
 		os.Exit(3)
 	}
 
-	path, output := getFlags(args[1:])
+	if args[0] == "migrate" {
+		return getMigrateHandler(args[1:])
+	}
+
+	flags := getFlags(args[1:])
 
 	switch args[0] {
-	case "migrate":
-		return func() error {
-			return migrate.Migrate(path, output)
-		}
 	case "generate":
 		return func() error {
-			return generate.Generate(path, output)
+			return generate.Generate(flags.Path, flags.ProjectPath)
 		}
 	case "clean":
 		return func() error {
-			return clean.Clean(path, output)
+			return clean.Clean(flags.Path, flags.ProjectPath)
+		}
+	case "pull":
+		return func() error {
+			return pull.Pull(flags)
 		}
 	default:
 		fmt.Println(fmt.Sprintf("Command with name '%s' not found", args[0]))
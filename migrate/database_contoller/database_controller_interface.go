@@ -5,11 +5,14 @@ import (
 )
 
 type DatabaseControllerInterface interface {
-	dropTables() error
-	dropEnums() error
-	createTables(enumNames []string, modelNames []string, models []schema_model.Model) error
-	createEnums(enums []schema_model.Enum) error
-	RunMigrations(schema *schema_model.GoRelSchema, enumNames []string, modelNames []string) error
+	getSnapshot() (databaseSnapshot, error)
+	createSnapshot(schema *schema_model.GoRelSchema, enumNames []string, modelNames []string) (databaseSnapshot, error)
+	generateMigrationSteps(diff snapshotDiff) []MigrationStep
+	createSchemaProperty(tableName string, column snapshotColumn) (property schema_model.Property, inlineEnum *snapshotEnum, warnings []string)
+	createMigrationsTable() error
+	GetAppliedMigrations() ([]AppliedMigration, error)
+	ApplyMigration(name string, checksum string, script string) error
+	RollbackMigration(name string, script string) error
 	Close() error
 	checkConnection() error
 }
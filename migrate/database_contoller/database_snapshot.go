@@ -0,0 +1,590 @@
+package database_contoller
+
+import (
+	"GoRelCli/models/error_model/database_error"
+	"GoRelCli/models/schema_model"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// databaseSnapshot is a provider independent description of a database structure.
+// It is created either from GoRelSchema (target state) or by introspecting live database (current state).
+type databaseSnapshot struct {
+	enums  []snapshotEnum
+	tables []snapshotTable
+}
+
+type snapshotEnum struct {
+	name   string
+	values []string
+}
+
+type snapshotColumn struct {
+	name          string
+	dataType      string
+	enumName      string
+	nullable      bool
+	defaultValue  string
+	autoincrement bool
+}
+
+type snapshotConstraint struct {
+	name    string
+	columns []string
+}
+
+type snapshotForeignKey struct {
+	name             string
+	columns          []string
+	referenceTable   string
+	referenceColumns []string
+	deferrable       bool
+	// onDelete and onUpdate are sql clauses of referential actions (e.g. CASCADE), empty for NO ACTION
+	onDelete string
+	onUpdate string
+}
+
+// snapshotIndex is an index created with CREATE INDEX. Unique indexes without options are stored as unique constraints.
+type snapshotIndex struct {
+	name    string
+	columns []string
+	// descending contains columns sorted in descending order
+	descending []string
+	unique     bool
+	// method is empty for the default method of the provider (btree)
+	method string
+	where  string
+}
+
+type snapshotTable struct {
+	name        string
+	columns     []snapshotColumn
+	primaryKey  snapshotConstraint
+	uniques     []snapshotConstraint
+	indexes     []snapshotIndex
+	foreignKeys []snapshotForeignKey
+}
+
+type tableColumn struct {
+	table  string
+	column snapshotColumn
+}
+
+type columnChange struct {
+	table   string
+	current snapshotColumn
+	target  snapshotColumn
+}
+
+type enumChange struct {
+	current snapshotEnum
+	target  snapshotEnum
+}
+
+type tableConstraint struct {
+	table      string
+	constraint snapshotConstraint
+}
+
+type tableIndex struct {
+	table string
+	index snapshotIndex
+}
+
+type tableForeignKey struct {
+	table      string
+	foreignKey snapshotForeignKey
+}
+
+// snapshotDiff contains every change that should be applied to current snapshot to get target snapshot
+type snapshotDiff struct {
+	current            databaseSnapshot
+	target             databaseSnapshot
+	createdEnums       []snapshotEnum
+	droppedEnums       []snapshotEnum
+	alteredEnums       []enumChange
+	createdTables      []snapshotTable
+	droppedTables      []snapshotTable
+	addedColumns       []tableColumn
+	droppedColumns     []tableColumn
+	alteredColumns     []columnChange
+	addedPrimaryKeys   []tableConstraint
+	droppedPrimaryKeys []tableConstraint
+	addedUniques       []tableConstraint
+	droppedUniques     []tableConstraint
+	addedIndexes       []tableIndex
+	droppedIndexes     []tableIndex
+	addedForeignKeys   []tableForeignKey
+	droppedForeignKeys []tableForeignKey
+}
+
+func (d snapshotDiff) isEmpty() bool {
+	return len(d.createdEnums) == 0 && len(d.droppedEnums) == 0 && len(d.alteredEnums) == 0 &&
+		len(d.createdTables) == 0 && len(d.droppedTables) == 0 &&
+		len(d.addedColumns) == 0 && len(d.droppedColumns) == 0 && len(d.alteredColumns) == 0 &&
+		len(d.addedPrimaryKeys) == 0 && len(d.droppedPrimaryKeys) == 0 &&
+		len(d.addedUniques) == 0 && len(d.droppedUniques) == 0 &&
+		len(d.addedIndexes) == 0 && len(d.droppedIndexes) == 0 &&
+		len(d.addedForeignKeys) == 0 && len(d.droppedForeignKeys) == 0
+}
+
+func (s databaseSnapshot) findTable(name string) (snapshotTable, bool) {
+	for _, table := range s.tables {
+		if table.name == name {
+			return table, true
+		}
+	}
+	return snapshotTable{}, false
+}
+
+func (s databaseSnapshot) findEnum(name string) (snapshotEnum, bool) {
+	for _, enum := range s.enums {
+		if enum.name == name {
+			return enum, true
+		}
+	}
+	return snapshotEnum{}, false
+}
+
+func (t snapshotTable) findColumn(name string) (snapshotColumn, bool) {
+	for _, column := range t.columns {
+		if column.name == name {
+			return column, true
+		}
+	}
+	return snapshotColumn{}, false
+}
+
+func (c snapshotColumn) equals(other snapshotColumn) bool {
+	return c.dataType == other.dataType &&
+		c.enumName == other.enumName &&
+		c.nullable == other.nullable &&
+		c.defaultValue == other.defaultValue &&
+		c.autoincrement == other.autoincrement
+}
+
+func (c snapshotConstraint) equals(other snapshotConstraint) bool {
+	return c.name == other.name && slices.Equal(c.columns, other.columns)
+}
+
+func (i snapshotIndex) equals(other snapshotIndex) bool {
+	return i.name == other.name &&
+		slices.Equal(i.columns, other.columns) &&
+		slices.Equal(i.descending, other.descending) &&
+		i.unique == other.unique &&
+		i.method == other.method &&
+		comparableIndexPredicate(i.where) == comparableIndexPredicate(other.where)
+}
+
+// isUniqueConstraint checks if index can be created as unique constraint, such indexes are stored in uniques of the table
+func (i snapshotIndex) isUniqueConstraint() bool {
+	return i.unique && len(i.descending) == 0 && i.method == "" && i.where == ""
+}
+
+// indexPredicateCastRegexp matches type casts added by the database to predicates of partial indexes (e.g. 'active'::text)
+var indexPredicateCastRegexp = regexp.MustCompile(`::("[^"]+"|character varying|double precision|timestamp with(out)? time zone|[a-zA-Z_]+)(\[\])?`)
+
+// comparableIndexPredicate removes parts of the predicate that are changed by the database when it stores the index
+// (quotes of identifiers, parentheses, casts and whitespace), so predicates from the schema can be compared with introspected ones
+func comparableIndexPredicate(predicate string) string {
+	predicate = indexPredicateCastRegexp.ReplaceAllString(predicate, "")
+	predicate = strings.NewReplacer("\"", "", "`", "", "(", "", ")", "").Replace(predicate)
+	return strings.Join(strings.Fields(predicate), " ")
+}
+
+func (f snapshotForeignKey) equals(other snapshotForeignKey) bool {
+	return f.name == other.name &&
+		slices.Equal(f.columns, other.columns) &&
+		f.referenceTable == other.referenceTable &&
+		slices.Equal(f.referenceColumns, other.referenceColumns) &&
+		f.deferrable == other.deferrable &&
+		f.onDelete == other.onDelete &&
+		f.onUpdate == other.onUpdate
+}
+
+// normalizeReferentialAction converts action read from the database to the action of the snapshot
+func normalizeReferentialAction(action string) string {
+	if action == "NO ACTION" {
+		return ""
+	}
+	return action
+}
+
+// generateReferentialActions creates ON DELETE and ON UPDATE clauses of the foreign key, they are the same for every provider
+func generateReferentialActions(foreignKey snapshotForeignKey) string {
+	var clauses string
+	if foreignKey.onDelete != "" {
+		clauses += " ON DELETE " + foreignKey.onDelete
+	}
+	if foreignKey.onUpdate != "" {
+		clauses += " ON UPDATE " + foreignKey.onUpdate
+	}
+	return clauses
+}
+
+func sortedKeys(tables map[string]*snapshotTable) []string {
+	keys := make([]string, 0, len(tables))
+	for key := range tables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isSubsequence checks if all values of current are present in target in the same order
+func isSubsequence(current []string, target []string) bool {
+	index := 0
+	for _, value := range target {
+		if index < len(current) && current[index] == value {
+			index++
+		}
+	}
+	return index == len(current)
+}
+
+// createSnapshotFromSchema creates target snapshot from the schema. Naming of constraints is the same for every provider,
+// createColumn converts property to the column of the provider.
+func createSnapshotFromSchema(schema *schema_model.GoRelSchema, enumNames []string, modelNames []string, createColumn func(property schema_model.Property, enumNames []string) (snapshotColumn, error)) (databaseSnapshot, error) {
+	var snapshot databaseSnapshot
+
+	for _, enum := range schema.Enums {
+		snapshot.enums = append(snapshot.enums, snapshotEnum{name: enum.Name, values: enum.Values})
+	}
+
+	for _, model := range schema.Models {
+		table := snapshotTable{
+			name:       model.Name,
+			primaryKey: snapshotConstraint{name: fmt.Sprintf("%s_pkey", model.Name)},
+		}
+
+		for propertyIndex, property := range model.Properties {
+			if property.RelationField != "" && property.ReferenceField != "" {
+				relation, err := defineRelation(model, schema.Models, propertyIndex)
+				if err != nil {
+					return databaseSnapshot{}, err
+				}
+				// named relations have own constraints, so several relations to the same model don't collide
+				name := fmt.Sprintf("fk_%s", relation.referenceModelName)
+				if property.RelationName != "" {
+					name = fmt.Sprintf("fk_%s_%s", relation.referenceModelName, property.RelationName)
+				}
+				table.foreignKeys = append(table.foreignKeys, snapshotForeignKey{
+					name:             name,
+					columns:          []string{relation.relationFieldName},
+					referenceTable:   relation.referenceModelName,
+					referenceColumns: []string{relation.referenceFieldName},
+					deferrable:       relation.relationType == OneToOne,
+					onDelete:         property.OnDelete.GetSql(),
+					onUpdate:         property.OnUpdate.GetSql(),
+				})
+				continue
+			}
+
+			propertyType := property.Type
+			if strings.Contains(propertyType, "[]") {
+				propertyType = propertyType[0 : len(propertyType)-2]
+			}
+			if strings.Contains(propertyType, "?") {
+				propertyType = propertyType[0 : len(propertyType)-1]
+			}
+			if slices.Contains(modelNames, propertyType) {
+				continue
+			}
+
+			// properties of model-level primary key are columns of the primary key too
+			property.Id = model.IsPrimaryKey(property.Name)
+			column, err := createColumn(property, enumNames)
+			if err != nil {
+				return databaseSnapshot{}, database_error.DatabaseError{
+					ErrorType: database_error.SqlGenerationError,
+					Text:      fmt.Sprintf("Can't create column for property %s of model %s: %s", property.Name, model.Name, err),
+				}
+			}
+			table.columns = append(table.columns, column)
+
+			if property.Unique {
+				table.uniques = append(table.uniques, snapshotConstraint{
+					name:    fmt.Sprintf("%s_%s_key", model.Name, property.Name),
+					columns: []string{property.Name},
+				})
+			}
+		}
+
+		table.primaryKey.columns = model.GetPrimaryKey()
+
+		for _, index := range model.Indexes {
+			snapshotIndex := snapshotIndex{
+				name:    index.GetName(model.Name),
+				columns: index.FieldNames(),
+				unique:  index.Unique,
+				where:   index.Where,
+			}
+			if index.Using != schema_model.BTree {
+				snapshotIndex.method = string(index.Using)
+			}
+			for _, field := range index.Fields {
+				if field.Sort == schema_model.Desc {
+					snapshotIndex.descending = append(snapshotIndex.descending, field.Name)
+				}
+			}
+
+			if snapshotIndex.isUniqueConstraint() {
+				table.uniques = append(table.uniques, snapshotConstraint{name: snapshotIndex.name, columns: snapshotIndex.columns})
+				continue
+			}
+			table.indexes = append(table.indexes, snapshotIndex)
+		}
+
+		snapshot.tables = append(snapshot.tables, table)
+	}
+
+	joinTables, err := createJoinTables(schema, enumNames, createColumn)
+	if err != nil {
+		return databaseSnapshot{}, err
+	}
+	snapshot.tables = append(snapshot.tables, joinTables...)
+
+	return snapshot, nil
+}
+
+// createJoinTables creates tables of implicit many-to-many relations. Every table has composite primary key of both columns
+// and index of the second column, rows are deleted together with records they reference.
+func createJoinTables(schema *schema_model.GoRelSchema, enumNames []string, createColumn func(property schema_model.Property, enumNames []string) (snapshotColumn, error)) ([]snapshotTable, error) {
+	var tables []snapshotTable
+	for _, model := range schema.Models {
+		for _, property := range model.Properties {
+			relatedIndex := slices.IndexFunc(schema.Models, func(related schema_model.Model) bool { return related.Name == property.GetBaseType() })
+			if relatedIndex == -1 {
+				continue
+			}
+			related := schema.Models[relatedIndex]
+			joinTable, isJoinTable := model.GetJoinTable(property, related)
+			// table is created once by the side stored in column A
+			if !isJoinTable || joinTable.Column != "A" {
+				continue
+			}
+
+			table := snapshotTable{
+				name:       joinTable.Name,
+				primaryKey: snapshotConstraint{name: fmt.Sprintf("%s_pkey", joinTable.Name), columns: []string{"A", "B"}},
+				indexes:    []snapshotIndex{{name: fmt.Sprintf("%s_B_idx", joinTable.Name), columns: []string{"B"}}},
+			}
+			for _, side := range []struct {
+				column string
+				model  schema_model.Model
+			}{{"A", model}, {"B", related}} {
+				primaryKey := side.model.GetPrimaryKey()
+				keyIndex := slices.IndexFunc(side.model.Properties, func(keyProperty schema_model.Property) bool {
+					return len(primaryKey) == 1 && keyProperty.Name == primaryKey[0]
+				})
+				if keyIndex == -1 {
+					return nil, database_error.DatabaseError{
+						ErrorType: database_error.SqlGenerationError,
+						Text:      fmt.Sprintf("Can't create join table %s, model %s should have single id property", joinTable.Name, side.model.Name),
+					}
+				}
+				keyProperty := side.model.Properties[keyIndex]
+
+				column, err := createColumn(schema_model.Property{Name: side.column, Type: keyProperty.GetBaseType(), Id: true}, enumNames)
+				if err != nil {
+					return nil, database_error.DatabaseError{
+						ErrorType: database_error.SqlGenerationError,
+						Text:      fmt.Sprintf("Can't create column %s of join table %s: %s", side.column, joinTable.Name, err),
+					}
+				}
+				table.columns = append(table.columns, column)
+				table.foreignKeys = append(table.foreignKeys, snapshotForeignKey{
+					name:             fmt.Sprintf("%s_%s_fkey", joinTable.Name, side.column),
+					columns:          []string{side.column},
+					referenceTable:   side.model.Name,
+					referenceColumns: []string{keyProperty.Name},
+					onDelete:         schema_model.Cascade.GetSql(),
+					onUpdate:         schema_model.Cascade.GetSql(),
+				})
+			}
+			tables = append(tables, table)
+		}
+	}
+	return tables, nil
+}
+
+func diffEnums(diff *snapshotDiff) {
+	for _, targetEnum := range diff.target.enums {
+		currentEnum, exists := diff.current.findEnum(targetEnum.name)
+		if !exists {
+			diff.createdEnums = append(diff.createdEnums, targetEnum)
+			continue
+		}
+		if !slices.Equal(currentEnum.values, targetEnum.values) {
+			diff.alteredEnums = append(diff.alteredEnums, enumChange{current: currentEnum, target: targetEnum})
+		}
+	}
+
+	for _, currentEnum := range diff.current.enums {
+		if _, exists := diff.target.findEnum(currentEnum.name); !exists {
+			diff.droppedEnums = append(diff.droppedEnums, currentEnum)
+		}
+	}
+}
+
+func diffConstraints(table string, current []snapshotConstraint, target []snapshotConstraint) (added []tableConstraint, dropped []tableConstraint) {
+	for _, targetConstraint := range target {
+		found := slices.ContainsFunc(current, func(c snapshotConstraint) bool { return c.equals(targetConstraint) })
+		if !found {
+			added = append(added, tableConstraint{table: table, constraint: targetConstraint})
+		}
+	}
+	for _, currentConstraint := range current {
+		found := slices.ContainsFunc(target, func(c snapshotConstraint) bool { return c.equals(currentConstraint) })
+		if !found {
+			dropped = append(dropped, tableConstraint{table: table, constraint: currentConstraint})
+		}
+	}
+	return added, dropped
+}
+
+func diffIndexes(table string, current []snapshotIndex, target []snapshotIndex) (added []tableIndex, dropped []tableIndex) {
+	for _, targetIndex := range target {
+		found := slices.ContainsFunc(current, func(i snapshotIndex) bool { return i.equals(targetIndex) })
+		if !found {
+			added = append(added, tableIndex{table: table, index: targetIndex})
+		}
+	}
+	for _, currentIndex := range current {
+		found := slices.ContainsFunc(target, func(i snapshotIndex) bool { return i.equals(currentIndex) })
+		if !found {
+			dropped = append(dropped, tableIndex{table: table, index: currentIndex})
+		}
+	}
+	return added, dropped
+}
+
+func diffForeignKeys(table string, current []snapshotForeignKey, target []snapshotForeignKey) (added []tableForeignKey, dropped []tableForeignKey) {
+	for _, targetForeignKey := range target {
+		found := slices.ContainsFunc(current, func(f snapshotForeignKey) bool { return f.equals(targetForeignKey) })
+		if !found {
+			added = append(added, tableForeignKey{table: table, foreignKey: targetForeignKey})
+		}
+	}
+	for _, currentForeignKey := range current {
+		found := slices.ContainsFunc(target, func(f snapshotForeignKey) bool { return f.equals(currentForeignKey) })
+		if !found {
+			dropped = append(dropped, tableForeignKey{table: table, foreignKey: currentForeignKey})
+		}
+	}
+	return added, dropped
+}
+
+func diffTable(diff *snapshotDiff, current snapshotTable, target snapshotTable) {
+	for _, targetColumn := range target.columns {
+		currentColumn, exists := current.findColumn(targetColumn.name)
+		if !exists {
+			diff.addedColumns = append(diff.addedColumns, tableColumn{table: target.name, column: targetColumn})
+			continue
+		}
+		if !currentColumn.equals(targetColumn) {
+			diff.alteredColumns = append(diff.alteredColumns, columnChange{table: target.name, current: currentColumn, target: targetColumn})
+		}
+	}
+
+	for _, currentColumn := range current.columns {
+		if _, exists := target.findColumn(currentColumn.name); !exists {
+			diff.droppedColumns = append(diff.droppedColumns, tableColumn{table: current.name, column: currentColumn})
+		}
+	}
+
+	var currentPrimaryKeys, targetPrimaryKeys []snapshotConstraint
+	if len(current.primaryKey.columns) != 0 {
+		currentPrimaryKeys = append(currentPrimaryKeys, current.primaryKey)
+	}
+	if len(target.primaryKey.columns) != 0 {
+		targetPrimaryKeys = append(targetPrimaryKeys, target.primaryKey)
+	}
+	addedPrimaryKeys, droppedPrimaryKeys := diffConstraints(target.name, currentPrimaryKeys, targetPrimaryKeys)
+	diff.addedPrimaryKeys = append(diff.addedPrimaryKeys, addedPrimaryKeys...)
+	diff.droppedPrimaryKeys = append(diff.droppedPrimaryKeys, droppedPrimaryKeys...)
+
+	addedUniques, droppedUniques := diffConstraints(target.name, current.uniques, target.uniques)
+	diff.addedUniques = append(diff.addedUniques, addedUniques...)
+	diff.droppedUniques = append(diff.droppedUniques, droppedUniques...)
+
+	addedIndexes, droppedIndexes := diffIndexes(target.name, current.indexes, target.indexes)
+	diff.addedIndexes = append(diff.addedIndexes, addedIndexes...)
+	diff.droppedIndexes = append(diff.droppedIndexes, droppedIndexes...)
+
+	addedForeignKeys, droppedForeignKeys := diffForeignKeys(target.name, current.foreignKeys, target.foreignKeys)
+	diff.addedForeignKeys = append(diff.addedForeignKeys, addedForeignKeys...)
+	diff.droppedForeignKeys = append(diff.droppedForeignKeys, droppedForeignKeys...)
+}
+
+func diffSnapshots(current databaseSnapshot, target databaseSnapshot) snapshotDiff {
+	diff := snapshotDiff{
+		current: current,
+		target:  target,
+	}
+
+	diffEnums(&diff)
+
+	for _, targetTable := range target.tables {
+		currentTable, exists := current.findTable(targetTable.name)
+		if !exists {
+			diff.createdTables = append(diff.createdTables, targetTable)
+			for _, foreignKey := range targetTable.foreignKeys {
+				diff.addedForeignKeys = append(diff.addedForeignKeys, tableForeignKey{table: targetTable.name, foreignKey: foreignKey})
+			}
+			for _, index := range targetTable.indexes {
+				diff.addedIndexes = append(diff.addedIndexes, tableIndex{table: targetTable.name, index: index})
+			}
+			continue
+		}
+		diffTable(&diff, currentTable, targetTable)
+	}
+
+	for _, currentTable := range current.tables {
+		if _, exists := target.findTable(currentTable.name); !exists {
+			diff.droppedTables = append(diff.droppedTables, currentTable)
+		}
+	}
+
+	recreateForeignKeysOnRetypedColumns(&diff)
+
+	return diff
+}
+
+// recreateForeignKeysOnRetypedColumns drops foreign keys that use columns with changed types and creates them again
+// after columns are altered, because database can't change type of the column used in foreign key
+func recreateForeignKeysOnRetypedColumns(diff *snapshotDiff) {
+	isRetyped := func(table string, columns []string) bool {
+		for _, change := range diff.alteredColumns {
+			if change.table == table && slices.Contains(columns, change.target.name) && (change.current.dataType != change.target.dataType || change.current.enumName != change.target.enumName) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, currentTable := range diff.current.tables {
+		targetTable, exists := diff.target.findTable(currentTable.name)
+		if !exists {
+			continue
+		}
+		for _, foreignKey := range currentTable.foreignKeys {
+			if !isRetyped(currentTable.name, foreignKey.columns) && !isRetyped(foreignKey.referenceTable, foreignKey.referenceColumns) {
+				continue
+			}
+			isDropped := slices.ContainsFunc(diff.droppedForeignKeys, func(f tableForeignKey) bool {
+				return f.table == currentTable.name && f.foreignKey.equals(foreignKey)
+			})
+			if isDropped {
+				continue
+			}
+			diff.droppedForeignKeys = append(diff.droppedForeignKeys, tableForeignKey{table: currentTable.name, foreignKey: foreignKey})
+			if slices.ContainsFunc(targetTable.foreignKeys, func(f snapshotForeignKey) bool { return f.equals(foreignKey) }) {
+				diff.addedForeignKeys = append(diff.addedForeignKeys, tableForeignKey{table: currentTable.name, foreignKey: foreignKey})
+			}
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package database_contoller
+
+import (
+	"GoRelCli/models/schema_model"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testUserSnapshot returns snapshot with User and Post tables, changes of the test case are applied to it by edit
+func testUserSnapshot(edit func(user *snapshotTable, post *snapshotTable, snapshot *databaseSnapshot)) databaseSnapshot {
+	user := snapshotTable{
+		name: "User",
+		columns: []snapshotColumn{
+			{name: "id", dataType: "integer", autoincrement: true},
+			{name: "email", dataType: "text"},
+			{name: "name", dataType: "text", nullable: true},
+			{name: "role", dataType: "UserRole", enumName: "UserRole", defaultValue: "user"},
+		},
+		primaryKey: snapshotConstraint{name: "User_pkey", columns: []string{"id"}},
+		uniques:    []snapshotConstraint{{name: "User_email_key", columns: []string{"email"}}},
+	}
+	post := snapshotTable{
+		name: "Post",
+		columns: []snapshotColumn{
+			{name: "id", dataType: "integer", autoincrement: true},
+			{name: "authorId", dataType: "integer"},
+			{name: "title", dataType: "text"},
+		},
+		primaryKey:  snapshotConstraint{name: "Post_pkey", columns: []string{"id"}},
+		indexes:     []snapshotIndex{{name: "Post_title_idx", columns: []string{"title"}}},
+		foreignKeys: []snapshotForeignKey{{name: "fk_User", columns: []string{"authorId"}, referenceTable: "User", referenceColumns: []string{"id"}}},
+	}
+	snapshot := databaseSnapshot{enums: []snapshotEnum{{name: "UserRole", values: []string{"user", "admin"}}}}
+	if edit != nil {
+		edit(&user, &post, &snapshot)
+	}
+	snapshot.tables = []snapshotTable{user, post}
+	return snapshot
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	tests := []struct {
+		name   string
+		edit   func(user *snapshotTable, post *snapshotTable, snapshot *databaseSnapshot)
+		result []string
+	}{
+		{
+			name: "same snapshot",
+		},
+		{
+			name: "add column",
+			edit: func(user *snapshotTable, post *snapshotTable, snapshot *databaseSnapshot) {
+				user.columns = append(user.columns, snapshotColumn{name: "age", dataType: "integer", nullable: true})
+			},
+			result: []string{"add column User.age"},
+		},
+		{
+			name: "drop column",
+			edit: func(user *snapshotTable, post *snapshotTable, snapshot *databaseSnapshot) {
+				user.columns = slices.Delete(user.columns, 2, 3)
+			},
+			result: []string{"drop column User.name"},
+		},
+		{
+			name: "retype column",
+			edit: func(user *snapshotTable, post *snapshotTable, snapshot *databaseSnapshot) {
+				post.columns[2].dataType = "varchar(191)"
+			},
+			result: []string{"alter column Post.title"},
+		},
+		{
+			name: "nullability change",
+			edit: func(user *snapshotTable, post *snapshotTable, snapshot *databaseSnapshot) {
+				user.columns[2].nullable = false
+			},
+			result: []string{"alter column User.name"},
+		},
+		{
+			name: "default value change",
+			edit: func(user *snapshotTable, post *snapshotTable, snapshot *databaseSnapshot) {
+				user.columns[3].defaultValue = "admin"
+			},
+			result: []string{"alter column User.role"},
+		},
+		{
+			name: "create and drop table",
+			edit: func(user *snapshotTable, post *snapshotTable, snapshot *databaseSnapshot) {
+				post.name = "Article"
+				post.foreignKeys[0].name = "fk_Article"
+			},
+			result: []string{"create table Article", "drop table Post", "add index Article.Post_title_idx", "add foreign key Article.fk_Article"},
+		},
+		{
+			name: "append enum value",
+			edit: func(user *snapshotTable, post *snapshotTable, snapshot *databaseSnapshot) {
+				snapshot.enums[0].values = []string{"user", "admin", "moderator"}
+			},
+			result: []string{"alter enum UserRole [user admin] -> [user admin moderator]"},
+		},
+		{
+			name: "reorder enum values",
+			edit: func(user *snapshotTable, post *snapshotTable, snapshot *databaseSnapshot) {
+				snapshot.enums[0].values = []string{"admin", "user"}
+			},
+			result: []string{"alter enum UserRole [user admin] -> [admin user]"},
+		},
+		{
+			name: "create and drop enum",
+			edit: func(user *snapshotTable, post *snapshotTable, snapshot *databaseSnapshot) {
+				snapshot.enums[0].name = "Role"
+				user.columns[3].dataType = "Role"
+				user.columns[3].enumName = "Role"
+			},
+			result: []string{"create enum Role", "drop enum UserRole", "alter column User.role"},
+		},
+		{
+			name: "primary key change",
+			edit: func(user *snapshotTable, post *snapshotTable, snapshot *databaseSnapshot) {
+				post.primaryKey.columns = []string{"id", "authorId"}
+			},
+			result: []string{"drop primary key Post.Post_pkey [id]", "add primary key Post.Post_pkey [id authorId]"},
+		},
+		{
+			name: "drop primary key",
+			edit: func(user *snapshotTable, post *snapshotTable, snapshot *databaseSnapshot) {
+				post.primaryKey = snapshotConstraint{}
+			},
+			result: []string{"drop primary key Post.Post_pkey [id]"},
+		},
+		{
+			name: "unique constraint becomes index",
+			edit: func(user *snapshotTable, post *snapshotTable, snapshot *databaseSnapshot) {
+				user.uniques = nil
+				user.indexes = []snapshotIndex{{name: "User_email_key", columns: []string{"email"}, unique: true, descending: []string{"email"}}}
+			},
+			result: []string{"drop unique User.User_email_key", "add index User.User_email_key"},
+		},
+		{
+			name: "index options change",
+			edit: func(user *snapshotTable, post *snapshotTable, snapshot *databaseSnapshot) {
+				post.indexes[0].method = "hash"
+			},
+			result: []string{"drop index Post.Post_title_idx", "add index Post.Post_title_idx"},
+		},
+		{
+			name: "referential action change",
+			edit: func(user *snapshotTable, post *snapshotTable, snapshot *databaseSnapshot) {
+				post.foreignKeys[0].onDelete = "CASCADE"
+			},
+			result: []string{"drop foreign key Post.fk_User", "add foreign key Post.fk_User"},
+		},
+		{
+			name: "retype foreign key column",
+			edit: func(user *snapshotTable, post *snapshotTable, snapshot *databaseSnapshot) {
+				post.columns[1].dataType = "bigint"
+			},
+			result: []string{"alter column Post.authorId", "drop foreign key Post.fk_User", "add foreign key Post.fk_User"},
+		},
+		{
+			name: "retype referenced column",
+			edit: func(user *snapshotTable, post *snapshotTable, snapshot *databaseSnapshot) {
+				user.columns[0] = snapshotColumn{name: "id", dataType: "text"}
+				post.columns[1].dataType = "text"
+			},
+			result: []string{"alter column User.id", "alter column Post.authorId", "drop foreign key Post.fk_User", "add foreign key Post.fk_User"},
+		},
+		{
+			name: "changed foreign key on retyped column is not duplicated",
+			edit: func(user *snapshotTable, post *snapshotTable, snapshot *databaseSnapshot) {
+				post.columns[1].dataType = "bigint"
+				post.foreignKeys[0].onDelete = "CASCADE"
+			},
+			result: []string{"alter column Post.authorId", "drop foreign key Post.fk_User", "add foreign key Post.fk_User"},
+		},
+		{
+			name: "nullability change keeps foreign key",
+			edit: func(user *snapshotTable, post *snapshotTable, snapshot *databaseSnapshot) {
+				post.columns[1].nullable = true
+			},
+			result: []string{"alter column Post.authorId"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diff := diffSnapshots(testUserSnapshot(nil), testUserSnapshot(test.edit))
+			if changes := describeDiff(diff); !slices.Equal(changes, test.result) {
+				t.Errorf("diff:\n%s\nwant:\n%s", strings.Join(changes, "\n"), strings.Join(test.result, "\n"))
+			}
+			if diff.isEmpty() != (len(test.result) == 0) {
+				t.Errorf("isEmpty() = %v for changes %v", diff.isEmpty(), test.result)
+			}
+		})
+	}
+}
+
+func TestIsSubsequence(t *testing.T) {
+	tests := []struct {
+		current []string
+		target  []string
+		result  bool
+	}{
+		{current: []string{"user", "admin"}, target: []string{"user", "admin"}, result: true},
+		{current: []string{"user", "admin"}, target: []string{"guest", "user", "moderator", "admin", "owner"}, result: true},
+		{current: []string{"user", "admin"}, target: []string{"admin", "user"}, result: false},
+		{current: []string{"user", "admin"}, target: []string{"user"}, result: false},
+		{current: []string{"user", "admin"}, target: []string{"user", "moderator"}, result: false},
+	}
+
+	for _, test := range tests {
+		if result := isSubsequence(test.current, test.target); result != test.result {
+			t.Errorf("isSubsequence(%v, %v) = %v, want %v", test.current, test.target, result, test.result)
+		}
+	}
+}
+
+func TestIndexUniqueConstraintSplit(t *testing.T) {
+	_, snapshot := testSnapshot(t, schema_model.PostgreSQL, `
+models:
+  - name: Post
+    properties:
+      - name: id
+        type: int
+        default: autoincrement()
+        id: true
+      - name: slug
+        type: string
+        unique: true
+      - name: authorName
+        type: string
+      - name: title
+        type: string
+    indexes:
+      - fields: [{name: authorName}, {name: title}]
+        unique: true
+      - name: Post_title_desc_key
+        fields: [{name: title, sort: desc}]
+        unique: true
+      - name: Post_title_hash_idx
+        fields: [{name: title}]
+        using: hash
+      - name: Post_title_where_key
+        fields: [{name: title}]
+        unique: true
+        where: slug IS NOT NULL
+`)
+	table, _ := snapshot.findTable("Post")
+
+	var uniques, indexes []string
+	for _, unique := range table.uniques {
+		uniques = append(uniques, unique.name)
+	}
+	for _, index := range table.indexes {
+		indexes = append(indexes, index.name)
+	}
+
+	wantUniques := []string{"Post_slug_key", "Post_authorName_title_key"}
+	if !slices.Equal(uniques, wantUniques) {
+		t.Errorf("uniques = %v, want %v", uniques, wantUniques)
+	}
+	wantIndexes := []string{"Post_title_desc_key", "Post_title_hash_idx", "Post_title_where_key"}
+	if !slices.Equal(indexes, wantIndexes) {
+		t.Errorf("indexes = %v, want %v", indexes, wantIndexes)
+	}
+}
+
+func TestNormalizeDefaultValue(t *testing.T) {
+	postgres := &PostgresController{}
+	postgresTests := []struct {
+		expression    string
+		result        string
+		autoincrement bool
+	}{
+		{expression: "", result: ""},
+		{expression: `nextval('"User_id_seq"'::regclass)`, autoincrement: true},
+		{expression: `'user'::"UserRole"`, result: "user"},
+		{expression: "'it''s'::text", result: "it's"},
+		{expression: "'untitled'::character varying", result: "untitled"},
+		{expression: "gen_random_uuid()", result: "gen_random_uuid()"},
+		{expression: "now()", result: "now()"},
+		{expression: "CURRENT_TIMESTAMP", result: "CURRENT_TIMESTAMP"},
+		{expression: "42", result: "42"},
+		{expression: "(-1)", result: "-1"},
+		{expression: "false", result: "false"},
+		{expression: "'{}'::text[]", result: "{}"},
+	}
+	for _, test := range postgresTests {
+		result, autoincrement := postgres.normalizeDefaultValue(test.expression)
+		if result != test.result || autoincrement != test.autoincrement {
+			t.Errorf("postgres normalizeDefaultValue(%q) = %q, %v, want %q, %v", test.expression, result, autoincrement, test.result, test.autoincrement)
+		}
+	}
+
+	mysql := &MySqlController{}
+	sqlite := &SqliteController{}
+	tests := []struct {
+		provider   string
+		normalize  func(string) string
+		expression string
+		result     string
+	}{
+		{provider: "mysql", normalize: mysql.normalizeDefaultValue, expression: "NULL", result: ""},
+		{provider: "mysql", normalize: mysql.normalizeDefaultValue, expression: "(uuid())", result: "uuid()"},
+		{provider: "mysql", normalize: mysql.normalizeDefaultValue, expression: "CURRENT_TIMESTAMP(6)", result: "CURRENT_TIMESTAMP(6)"},
+		{provider: "mysql", normalize: mysql.normalizeDefaultValue, expression: "'it''s'", result: "it's"},
+		{provider: "mysql", normalize: mysql.normalizeDefaultValue, expression: "untitled", result: "untitled"},
+		{provider: "mysql", normalize: mysql.normalizeDefaultValue, expression: "0", result: "0"},
+		{provider: "sqlite", normalize: sqlite.normalizeDefaultValue, expression: "'user'", result: "user"},
+		{provider: "sqlite", normalize: sqlite.normalizeDefaultValue, expression: "'it''s'", result: "it's"},
+		{provider: "sqlite", normalize: sqlite.normalizeDefaultValue, expression: "CURRENT_TIMESTAMP", result: "CURRENT_TIMESTAMP"},
+		{provider: "sqlite", normalize: sqlite.normalizeDefaultValue, expression: "1", result: "1"},
+	}
+	for _, test := range tests {
+		if result := test.normalize(test.expression); result != test.result {
+			t.Errorf("%s normalizeDefaultValue(%q) = %q, want %q", test.provider, test.expression, result, test.result)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package database_contoller
+
+import (
+	"GoRelCli/models/schema_model"
+	"GoRelCli/utils/validator"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// loadTestSchema parses and validates schema written in yml, connection is added for the provider
+func loadTestSchema(t *testing.T, provider schema_model.Provider, yml string) (*schema_model.GoRelSchema, []string, []string) {
+	t.Helper()
+	var schema schema_model.GoRelSchema
+	if err := yaml.Unmarshal([]byte(yml), &schema); err != nil {
+		t.Fatalf("can't parse schema: %s", err)
+	}
+	schema.Connection = schema_model.Connection{Provider: provider}
+	enumNames, modelNames, err := validator.ValidateSchema(&schema)
+	if err != nil {
+		t.Fatalf("invalid schema: %s", err)
+	}
+	return &schema, enumNames, modelNames
+}
+
+// testSnapshot creates snapshot of the schema with offline controller of the provider
+func testSnapshot(t *testing.T, provider schema_model.Provider, yml string) (DatabaseControllerInterface, databaseSnapshot) {
+	t.Helper()
+	controller, err := NewOfflineDatabaseController(provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, enumNames, modelNames := loadTestSchema(t, provider, yml)
+	snapshot, err := controller.createSnapshot(schema, enumNames, modelNames)
+	if err != nil {
+		t.Fatalf("can't create snapshot: %s", err)
+	}
+	return controller, snapshot
+}
+
+// assertGolden compares got with the content of golden file, the file is rewritten when tests are run with -update
+func assertGolden(t *testing.T, path string, got string) {
+	t.Helper()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("can't read golden file (run tests with -update to create it): %s", err)
+	}
+	if got != string(want) {
+		t.Errorf("sql doesn't match %s\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// describeDiff lists changes of the diff in the order they are stored, so expected diffs are easy to read
+func describeDiff(diff snapshotDiff) []string {
+	var changes []string
+	for _, enum := range diff.createdEnums {
+		changes = append(changes, "create enum "+enum.name)
+	}
+	for _, enum := range diff.droppedEnums {
+		changes = append(changes, "drop enum "+enum.name)
+	}
+	for _, change := range diff.alteredEnums {
+		changes = append(changes, fmt.Sprintf("alter enum %s %v -> %v", change.target.name, change.current.values, change.target.values))
+	}
+	for _, table := range diff.createdTables {
+		changes = append(changes, "create table "+table.name)
+	}
+	for _, table := range diff.droppedTables {
+		changes = append(changes, "drop table "+table.name)
+	}
+	for _, column := range diff.addedColumns {
+		changes = append(changes, fmt.Sprintf("add column %s.%s", column.table, column.column.name))
+	}
+	for _, column := range diff.droppedColumns {
+		changes = append(changes, fmt.Sprintf("drop column %s.%s", column.table, column.column.name))
+	}
+	for _, change := range diff.alteredColumns {
+		changes = append(changes, fmt.Sprintf("alter column %s.%s", change.table, change.target.name))
+	}
+	for _, primaryKey := range diff.droppedPrimaryKeys {
+		changes = append(changes, fmt.Sprintf("drop primary key %s.%s %v", primaryKey.table, primaryKey.constraint.name, primaryKey.constraint.columns))
+	}
+	for _, primaryKey := range diff.addedPrimaryKeys {
+		changes = append(changes, fmt.Sprintf("add primary key %s.%s %v", primaryKey.table, primaryKey.constraint.name, primaryKey.constraint.columns))
+	}
+	for _, unique := range diff.droppedUniques {
+		changes = append(changes, fmt.Sprintf("drop unique %s.%s", unique.table, unique.constraint.name))
+	}
+	for _, unique := range diff.addedUniques {
+		changes = append(changes, fmt.Sprintf("add unique %s.%s", unique.table, unique.constraint.name))
+	}
+	for _, index := range diff.droppedIndexes {
+		changes = append(changes, fmt.Sprintf("drop index %s.%s", index.table, index.index.name))
+	}
+	for _, index := range diff.addedIndexes {
+		changes = append(changes, fmt.Sprintf("add index %s.%s", index.table, index.index.name))
+	}
+	for _, foreignKey := range diff.droppedForeignKeys {
+		changes = append(changes, fmt.Sprintf("drop foreign key %s.%s", foreignKey.table, foreignKey.foreignKey.name))
+	}
+	for _, foreignKey := range diff.addedForeignKeys {
+		changes = append(changes, fmt.Sprintf("add foreign key %s.%s", foreignKey.table, foreignKey.foreignKey.name))
+	}
+	return changes
+}
+
+// assertRoundTrip checks that introspected snapshot matches the schema it was created from
+// and that schema pulled from it creates the same snapshot, so neither of them produces a migration
+func assertRoundTrip(t *testing.T, controller DatabaseControllerInterface, provider schema_model.Provider, target databaseSnapshot, introspected databaseSnapshot) {
+	t.Helper()
+	if diff := diffSnapshots(introspected, target); !diff.isEmpty() {
+		t.Errorf("introspected snapshot differs from the schema:\n%s", strings.Join(describeDiff(diff), "\n"))
+	}
+
+	models, enums, warnings := createSchemaFromSnapshot(controller, introspected)
+	if len(warnings) != 0 {
+		t.Errorf("pull warnings: %v", warnings)
+	}
+	schema := schema_model.GoRelSchema{Connection: schema_model.Connection{Provider: provider}, Models: models, Enums: enums}
+	enumNames, modelNames, err := validator.ValidateSchema(&schema)
+	if err != nil {
+		t.Fatalf("invalid pulled schema: %s", err)
+	}
+	pulled, err := controller.createSnapshot(&schema, enumNames, modelNames)
+	if err != nil {
+		t.Fatalf("can't create snapshot of pulled schema: %s", err)
+	}
+	if diff := diffSnapshots(introspected, pulled); !diff.isEmpty() {
+		t.Errorf("pulled schema differs from the database:\n%s", strings.Join(describeDiff(diff), "\n"))
+	}
+}
+
+// replaceIndexes returns copy of the snapshot where indexes of the table are replaced with introspected ones
+func replaceIndexes(snapshot databaseSnapshot, tableName string, uniques []snapshotConstraint, indexes []snapshotIndex) databaseSnapshot {
+	result := databaseSnapshot{enums: snapshot.enums, tables: slices.Clone(snapshot.tables)}
+	for tableIndex := range result.tables {
+		if result.tables[tableIndex].name == tableName {
+			result.tables[tableIndex].uniques = uniques
+			result.tables[tableIndex].indexes = indexes
+		}
+	}
+	return result
+}
@@ -0,0 +1,89 @@
+package database_contoller
+
+import (
+	"GoRelCli/models/schema_model"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MigrationsTableName is the name of the table where applied migrations are stored. It is ignored while comparing database with schema.
+const MigrationsTableName = "_gorel_migrations"
+
+// MigrationStep is a single sql statement of a migration.
+// Warning is not empty when the statement can lead to data loss or can fail on non-empty tables.
+type MigrationStep struct {
+	Query   string
+	Warning string
+}
+
+// AppliedMigration is a record of the migrations table
+type AppliedMigration struct {
+	Name       string
+	Checksum   string
+	StartedAt  time.Time
+	FinishedAt sql.NullTime
+	Failed     bool
+}
+
+// IsFinished returns true if migration was successfully applied
+func (m AppliedMigration) IsFinished() bool {
+	return m.FinishedAt.Valid && !m.Failed
+}
+
+// GenerateMigration compares current state of the database with the schema and returns statements required to
+// bring the database to the state described in schema (up) and statements that revert them (down)
+func GenerateMigration(controller DatabaseControllerInterface, schema *schema_model.GoRelSchema, enumNames []string, modelNames []string) (up []MigrationStep, down []MigrationStep, err error) {
+	current, err := controller.getSnapshot()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	target, err := controller.createSnapshot(schema, enumNames, modelNames)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	diff := diffSnapshots(current, target)
+	if diff.isEmpty() {
+		return nil, nil, nil
+	}
+
+	return controller.generateMigrationSteps(diff), controller.generateMigrationSteps(diffSnapshots(target, current)), nil
+}
+
+// GenerateInitialMigration returns statements that create everything described in schema in an empty database.
+// It does not read the database, so it can be used with offline controller.
+func GenerateInitialMigration(controller DatabaseControllerInterface, schema *schema_model.GoRelSchema, enumNames []string, modelNames []string) ([]MigrationStep, error) {
+	target, err := controller.createSnapshot(schema, enumNames, modelNames)
+	if err != nil {
+		return nil, err
+	}
+
+	return controller.generateMigrationSteps(diffSnapshots(databaseSnapshot{}, target)), nil
+}
+
+// GetWarnings returns warnings of all steps that have them
+func GetWarnings(steps []MigrationStep) []string {
+	var warnings []string
+	for _, step := range steps {
+		if step.Warning != "" {
+			warnings = append(warnings, step.Warning)
+		}
+	}
+	return warnings
+}
+
+// GenerateSqlScript joins statements of the migration into sql script. Warnings are added as comments before statements.
+func GenerateSqlScript(steps []MigrationStep) string {
+	var builder strings.Builder
+	for _, step := range steps {
+		if step.Warning != "" {
+			builder.WriteString(fmt.Sprintf("-- Warning: %s\n", step.Warning))
+		}
+		builder.WriteString(step.Query)
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
@@ -0,0 +1,893 @@
+package database_contoller
+
+import (
+	"GoRelCli/models/error_model/database_error"
+	"GoRelCli/models/schema_model"
+	"GoRelCli/utils/naming"
+	"database/sql"
+	"fmt"
+	_ "github.com/go-sql-driver/mysql"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const mySqlUuidExpression = "uuid()"
+
+const mySqlCurrentTimestamp = "CURRENT_TIMESTAMP(6)"
+
+// mySqlStringKeyType is used instead of text for strings that are part of keys or have default values.
+// 191 characters of utf8mb4 fit into the index key prefix limit of old InnoDB versions.
+const mySqlStringKeyType = "varchar(191)"
+
+// mySqlIntDisplayWidthRegexp matches display width of integer types returned by mysql older than 8.0.19 (e.g. int(11))
+var mySqlIntDisplayWidthRegexp = regexp.MustCompile(`^(int|bigint|smallint|mediumint)\(\d+\)`)
+
+// mySqlEnumValueRegexp matches values of inline enum type (e.g. enum('Admin','User'))
+var mySqlEnumValueRegexp = regexp.MustCompile(`'((?:[^']|'')*)'`)
+
+// mySqlTypeAliases contains mysql types that are not created by GoRel, but can be described by the closest schema type
+var mySqlTypeAliases = map[string]string{
+	"bigint":     "int",
+	"smallint":   "int",
+	"mediumint":  "int",
+	"tinyint":    "int",
+	"float":      "double",
+	"decimal":    "double",
+	"varchar":    "text",
+	"char":       "text",
+	"tinytext":   "text",
+	"mediumtext": "text",
+	"longtext":   "text",
+	"datetime":   "datetime(6)",
+	"timestamp":  "datetime(6)",
+	"date":       "datetime(6)",
+}
+
+// MySqlController works with mysql 8. Mysql has no enum types, so enums are declared inline in column type
+// (e.g. enum('Admin','User')) and changes of enums are applied as changes of columns.
+type MySqlController struct {
+	db *sql.DB
+}
+
+func (m *MySqlController) createSnapshotColumn(property schema_model.Property, enumNames []string) (snapshotColumn, error) {
+	column := snapshotColumn{name: property.Name}
+
+	propertyType := property.Type
+	isOptional := strings.HasSuffix(propertyType, "?")
+	if isOptional {
+		propertyType = propertyType[0 : len(propertyType)-1]
+	}
+
+	if slices.Contains(enumNames, propertyType) {
+		column.enumName = propertyType
+		column.nullable = isOptional
+	} else {
+		mySqlType, isValidType := property.GetMySqlType()
+		if !isValidType {
+			return snapshotColumn{}, database_error.DatabaseError{
+				ErrorType: database_error.SqlGenerationError,
+				Text:      fmt.Sprintf("Invalid property type provided: %s", property.Type),
+			}
+		}
+		column.dataType = strings.TrimSuffix(mySqlType, " NOT NULL")
+		column.nullable = !strings.HasSuffix(mySqlType, " NOT NULL")
+	}
+
+	switch property.Default {
+	case "":
+	case "autoincrement()":
+		column.autoincrement = true
+		column.nullable = false
+	case "uuid()":
+		column.defaultValue = mySqlUuidExpression
+	case "now()":
+		column.defaultValue = mySqlCurrentTimestamp
+	default:
+		value, err := property.ValidateDefaultValue()
+		if err != nil {
+			return snapshotColumn{}, err
+		}
+		if boolValue, isBool := value.(bool); isBool {
+			// booleans are stored as tinyint(1)
+			column.defaultValue = "0"
+			if boolValue {
+				column.defaultValue = "1"
+			}
+		} else {
+			column.defaultValue = property.Default
+		}
+	}
+
+	// text columns can't be used in keys and can't have literal default values
+	if column.dataType == "text" && (property.Id || property.Unique || column.defaultValue != "") {
+		column.dataType = mySqlStringKeyType
+	}
+
+	return column, nil
+}
+
+func (m *MySqlController) createSnapshot(schema *schema_model.GoRelSchema, enumNames []string, modelNames []string) (databaseSnapshot, error) {
+	snapshot, err := createSnapshotFromSchema(schema, enumNames, modelNames, m.createSnapshotColumn)
+	if err != nil {
+		return databaseSnapshot{}, err
+	}
+
+	for tableIndex := range snapshot.tables {
+		table := &snapshot.tables[tableIndex]
+		for columnIndex := range table.columns {
+			column := &table.columns[columnIndex]
+			if column.enumName != "" {
+				enum, _ := snapshot.findEnum(column.enumName)
+				column.dataType = m.generateEnumType(enum.values)
+				column.enumName = ""
+			}
+		}
+		for foreignKeyIndex := range table.foreignKeys {
+			foreignKey := &table.foreignKeys[foreignKeyIndex]
+			// names of foreign keys should be unique in the whole database and deferrable constraints are not supported
+			foreignKey.name = fmt.Sprintf("%s_%s_fkey", table.name, strings.Join(foreignKey.columns, "_"))
+			foreignKey.deferrable = false
+			foreignKey.onDelete = m.normalizeReferentialAction(foreignKey.onDelete)
+			foreignKey.onUpdate = m.normalizeReferentialAction(foreignKey.onUpdate)
+		}
+	}
+
+	// text columns of unique constraints and indexes are stored as varchar, because text can't be used in keys
+	for tableIndex := range snapshot.tables {
+		table := &snapshot.tables[tableIndex]
+		var indexedColumns []string
+		for _, unique := range table.uniques {
+			indexedColumns = append(indexedColumns, unique.columns...)
+		}
+		for _, index := range table.indexes {
+			indexedColumns = append(indexedColumns, index.columns...)
+		}
+		for columnIndex := range table.columns {
+			column := &table.columns[columnIndex]
+			if column.dataType == "text" && slices.Contains(indexedColumns, column.name) {
+				column.dataType = mySqlStringKeyType
+			}
+		}
+	}
+
+	// columns of foreign keys should have the same type as referenced columns
+	for tableIndex := range snapshot.tables {
+		table := &snapshot.tables[tableIndex]
+		for _, foreignKey := range table.foreignKeys {
+			referenceTable, _ := snapshot.findTable(foreignKey.referenceTable)
+			for index, columnName := range foreignKey.columns {
+				referenceColumn, _ := referenceTable.findColumn(foreignKey.referenceColumns[index])
+				for columnIndex := range table.columns {
+					column := &table.columns[columnIndex]
+					if column.name == columnName && column.dataType == "text" {
+						column.dataType = referenceColumn.dataType
+					}
+				}
+			}
+		}
+	}
+
+	snapshot.enums = nil
+	return snapshot, nil
+}
+
+// createSchemaProperty converts column to property. Enums are declared inline in mysql, so enum of the column is returned
+// with the name created from names of the table and the column.
+func (m *MySqlController) createSchemaProperty(tableName string, column snapshotColumn) (schema_model.Property, *snapshotEnum, []string) {
+	property := schema_model.Property{Name: column.name}
+	var inlineEnum *snapshotEnum
+	var warnings []string
+
+	if strings.HasPrefix(column.dataType, "enum(") {
+		inlineEnum = &snapshotEnum{name: naming.UpperFirst(tableName) + naming.UpperFirst(column.name)}
+		for _, matches := range mySqlEnumValueRegexp.FindAllStringSubmatch(column.dataType, -1) {
+			inlineEnum.values = append(inlineEnum.values, strings.ReplaceAll(matches[1], "''", "'"))
+		}
+		property.Type = inlineEnum.name
+		if column.nullable {
+			property.Type += "?"
+		}
+	} else {
+		dataType := column.dataType
+		if dataType == mySqlStringKeyType {
+			dataType = "text"
+		}
+		if _, isFound := schema_model.PropertyTypeFromMySqlType(dataType, column.nullable); !isFound {
+			baseType, _, _ := strings.Cut(dataType, "(")
+			alias, isAlias := mySqlTypeAliases[strings.TrimSuffix(baseType, " unsigned")]
+			if !isAlias {
+				alias = "text"
+			}
+			warnings = append(warnings, fmt.Sprintf("Type %s of column \"%s\" of table \"%s\" is not supported by the schema and is replaced with %s", column.dataType, column.name, tableName, alias))
+			dataType = alias
+		}
+
+		propertyType, _ := schema_model.PropertyTypeFromMySqlType(dataType, column.nullable)
+		property.Type = string(propertyType)
+	}
+
+	defaultValue := column.defaultValue
+	switch {
+	case column.autoincrement:
+		defaultValue = "autoincrement()"
+	case defaultValue == mySqlUuidExpression:
+		defaultValue = "uuid()"
+	case strings.HasPrefix(defaultValue, "CURRENT_TIMESTAMP"):
+		defaultValue = "now()"
+	case property.Type == schema_model.Boolean && (defaultValue == "0" || defaultValue == "1"):
+		defaultValue = strconv.FormatBool(defaultValue == "1")
+	}
+	warnings = append(warnings, setPropertyDefault(&property, tableName, defaultValue)...)
+
+	return property, inlineEnum, warnings
+}
+
+func (m *MySqlController) getSnapshot() (databaseSnapshot, error) {
+	var snapshot databaseSnapshot
+
+	tables, err := m.getTables()
+	if err != nil {
+		return databaseSnapshot{}, database_error.DatabaseError{
+			ErrorType: database_error.IntrospectionError,
+			Text:      fmt.Sprintf("Can't get tables: %s", err),
+		}
+	}
+
+	if err := m.getColumns(tables); err != nil {
+		return databaseSnapshot{}, database_error.DatabaseError{
+			ErrorType: database_error.IntrospectionError,
+			Text:      fmt.Sprintf("Can't get columns: %s", err),
+		}
+	}
+
+	if err := m.getConstraints(tables); err != nil {
+		return databaseSnapshot{}, database_error.DatabaseError{
+			ErrorType: database_error.IntrospectionError,
+			Text:      fmt.Sprintf("Can't get constraints: %s", err),
+		}
+	}
+
+	if err := m.getIndexes(tables); err != nil {
+		return databaseSnapshot{}, database_error.DatabaseError{
+			ErrorType: database_error.IntrospectionError,
+			Text:      fmt.Sprintf("Can't get indexes: %s", err),
+		}
+	}
+
+	for _, tableName := range sortedKeys(tables) {
+		snapshot.tables = append(snapshot.tables, *tables[tableName])
+	}
+
+	return snapshot, nil
+}
+
+func (m *MySqlController) generateMigrationSteps(diff snapshotDiff) []MigrationStep {
+	var steps []MigrationStep
+
+	// foreign keys of dropped tables are dropped first, because mysql can't drop referenced table
+	for _, table := range diff.droppedTables {
+		for _, foreignKey := range table.foreignKeys {
+			steps = append(steps, MigrationStep{Query: m.generateDropForeignKeySqlScript(table.name, foreignKey.name)})
+		}
+	}
+
+	for _, foreignKey := range diff.droppedForeignKeys {
+		steps = append(steps, MigrationStep{Query: m.generateDropForeignKeySqlScript(foreignKey.table, foreignKey.foreignKey.name)})
+	}
+
+	for _, primaryKey := range diff.droppedPrimaryKeys {
+		steps = append(steps, MigrationStep{Query: fmt.Sprintf("ALTER TABLE `%s` DROP PRIMARY KEY;", primaryKey.table)})
+	}
+
+	for _, unique := range diff.droppedUniques {
+		steps = append(steps, MigrationStep{Query: fmt.Sprintf("ALTER TABLE `%s` DROP INDEX `%s`;", unique.table, unique.constraint.name)})
+	}
+
+	for _, index := range diff.droppedIndexes {
+		steps = append(steps, MigrationStep{Query: fmt.Sprintf("ALTER TABLE `%s` DROP INDEX `%s`;", index.table, index.index.name)})
+	}
+
+	for _, table := range diff.droppedTables {
+		steps = append(steps, MigrationStep{
+			Query:   m.generateDeleteTableSqlScriptFromDbTableName(table.name),
+			Warning: fmt.Sprintf("Table \"%s\" will be dropped with all of its data", table.name),
+		})
+	}
+
+	for _, column := range diff.droppedColumns {
+		steps = append(steps, MigrationStep{
+			Query:   m.generateDropColumnSqlScript(column.table, column.column.name),
+			Warning: fmt.Sprintf("Column \"%s\" of table \"%s\" will be dropped with all of its data", column.column.name, column.table),
+		})
+	}
+
+	for _, table := range diff.createdTables {
+		steps = append(steps, MigrationStep{Query: m.generateCreateTableSqlScriptFromTable(table)})
+	}
+
+	for _, column := range diff.addedColumns {
+		step := MigrationStep{Query: m.generateAddColumnSqlScript(column.table, column.column)}
+		if !column.column.nullable && column.column.defaultValue == "" && !column.column.autoincrement {
+			step.Warning = fmt.Sprintf("Required column \"%s\" without default value is added to table \"%s\". Existing rows will get zero value of the type", column.column.name, column.table)
+		}
+		steps = append(steps, step)
+	}
+
+	for _, change := range diff.alteredColumns {
+		steps = append(steps, m.generateModifyColumnSqlScript(change))
+	}
+
+	for _, primaryKey := range diff.addedPrimaryKeys {
+		steps = append(steps, MigrationStep{Query: fmt.Sprintf("ALTER TABLE `%s` ADD PRIMARY KEY (%s);", primaryKey.table, m.generateColumnList(primaryKey.constraint.columns))})
+	}
+
+	for _, unique := range diff.addedUniques {
+		steps = append(steps, MigrationStep{
+			Query:   fmt.Sprintf("ALTER TABLE `%s` ADD UNIQUE INDEX `%s` (%s);", unique.table, unique.constraint.name, m.generateColumnList(unique.constraint.columns)),
+			Warning: fmt.Sprintf("Unique index \"%s\" is added to table \"%s\". Migration will fail if there are duplicate values", unique.constraint.name, unique.table),
+		})
+	}
+
+	for _, index := range diff.addedIndexes {
+		step := MigrationStep{Query: m.generateAddIndexSqlScript(index.table, index.index)}
+		if index.index.unique {
+			step.Warning = fmt.Sprintf("Unique index \"%s\" is added to table \"%s\". Migration will fail if there are duplicate values", index.index.name, index.table)
+		}
+		steps = append(steps, step)
+	}
+
+	for _, foreignKey := range diff.addedForeignKeys {
+		steps = append(steps, MigrationStep{Query: m.generateRelationsSqlScriptFromForeignKey(foreignKey.table, foreignKey.foreignKey)})
+	}
+
+	return steps
+}
+
+func (m *MySqlController) createMigrationsTable() error {
+	/*
+		CREATE TABLE IF NOT EXISTS `_gorel_migrations` (
+			`name` varchar(191) NOT NULL,
+			`checksum` varchar(64) NOT NULL,
+			`started_at` datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+			`finished_at` datetime(6),
+			`failed` tinyint(1) NOT NULL DEFAULT 0,
+			PRIMARY KEY (`name`)
+		);
+	*/
+	rawSqlString := fmt.Sprintf("CREATE TABLE IF NOT EXISTS `%s` (`name` varchar(191) NOT NULL, `checksum` varchar(64) NOT NULL, `started_at` datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6), `finished_at` datetime(6), `failed` tinyint(1) NOT NULL DEFAULT 0, PRIMARY KEY (`name`));", MigrationsTableName)
+	if _, err := m.db.Exec(rawSqlString); err != nil {
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't create migrations table: %s", err),
+		}
+	}
+	return nil
+}
+
+func (m *MySqlController) migrationsTableExists() (bool, error) {
+	var count int
+	if err := m.db.QueryRow("SELECT COUNT(*) FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?", MigrationsTableName).Scan(&count); err != nil {
+		return false, database_error.DatabaseError{
+			ErrorType: database_error.IntrospectionError,
+			Text:      fmt.Sprintf("Can't check if migrations table exists: %s", err),
+		}
+	}
+	return count != 0, nil
+}
+
+// GetAppliedMigrations returns records of the migrations table. It does not create the table, so database is not changed.
+func (m *MySqlController) GetAppliedMigrations() ([]AppliedMigration, error) {
+	exists, err := m.migrationsTableExists()
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	rows, err := m.db.Query(fmt.Sprintf("SELECT `name`, `checksum`, `started_at`, `finished_at`, `failed` FROM `%s` ORDER BY `name`", MigrationsTableName))
+	if err != nil {
+		return nil, database_error.DatabaseError{
+			ErrorType: database_error.IntrospectionError,
+			Text:      fmt.Sprintf("Can't get applied migrations: %s", err),
+		}
+	}
+	defer rows.Close()
+
+	var migrations []AppliedMigration
+	for rows.Next() {
+		var migration AppliedMigration
+		if err := rows.Scan(&migration.Name, &migration.Checksum, &migration.StartedAt, &migration.FinishedAt, &migration.Failed); err != nil {
+			return nil, database_error.DatabaseError{
+				ErrorType: database_error.IntrospectionError,
+				Text:      fmt.Sprintf("Can't get applied migrations: %s", err),
+			}
+		}
+		migrations = append(migrations, migration)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, database_error.DatabaseError{
+			ErrorType: database_error.IntrospectionError,
+			Text:      fmt.Sprintf("Can't get applied migrations: %s", err),
+		}
+	}
+
+	return migrations, nil
+}
+
+func (m *MySqlController) markMigrationAsFailed(name string) {
+	if _, err := m.db.Exec(fmt.Sprintf("UPDATE `%s` SET `failed` = 1, `finished_at` = CURRENT_TIMESTAMP(6) WHERE `name` = ?", MigrationsTableName), name); err != nil {
+		fmt.Println(fmt.Sprintf("Can't mark migration %s as failed: %s", name, err))
+	}
+}
+
+// ApplyMigration runs migration script. Mysql commits DDL statements implicitly, so failed migration can be applied partially.
+func (m *MySqlController) ApplyMigration(name string, checksum string, script string) error {
+	if err := m.createMigrationsTable(); err != nil {
+		return err
+	}
+
+	// Migration record is saved outside of transaction, so it is kept if migration fails
+	startMigrationQuery := fmt.Sprintf("INSERT INTO `%s` (`name`, `checksum`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `checksum` = VALUES(`checksum`), `started_at` = CURRENT_TIMESTAMP(6), `finished_at` = NULL, `failed` = 0", MigrationsTableName)
+	if _, err := m.db.Exec(startMigrationQuery, name, checksum); err != nil {
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't save migration %s: %s", name, err),
+		}
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		m.markMigrationAsFailed(name)
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't start transaction for migration %s: %s", name, err),
+		}
+	}
+
+	if _, err := tx.Exec(script); err != nil {
+		_ = tx.Rollback()
+		m.markMigrationAsFailed(name)
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't apply migration %s: %s", name, err),
+		}
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf("UPDATE `%s` SET `finished_at` = CURRENT_TIMESTAMP(6) WHERE `name` = ?", MigrationsTableName), name); err != nil {
+		_ = tx.Rollback()
+		m.markMigrationAsFailed(name)
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't save migration %s: %s", name, err),
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.markMigrationAsFailed(name)
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't commit migration %s: %s", name, err),
+		}
+	}
+
+	return nil
+}
+
+func (m *MySqlController) RollbackMigration(name string, script string) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't start transaction for rollback of migration %s: %s", name, err),
+		}
+	}
+
+	if _, err := tx.Exec(script); err != nil {
+		_ = tx.Rollback()
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't rollback migration %s: %s", name, err),
+		}
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf("DELETE FROM `%s` WHERE `name` = ?", MigrationsTableName), name); err != nil {
+		_ = tx.Rollback()
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't delete migration %s from migrations table: %s", name, err),
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't commit rollback of migration %s: %s", name, err),
+		}
+	}
+
+	return nil
+}
+
+func (m *MySqlController) Close() error {
+	if err := m.db.Close(); err != nil {
+		return database_error.DatabaseError{
+			ErrorType: database_error.CloseConnectionError,
+			Text:      fmt.Sprintf("Can't close connection to mysql db."),
+		}
+	}
+	return nil
+}
+
+func (m *MySqlController) getTables() (map[string]*snapshotTable, error) {
+	/*
+		SELECT TABLE_NAME
+		FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_TYPE = 'BASE TABLE' AND TABLE_NAME <> '_gorel_migrations';
+	*/
+	const rawSqlString = "SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_TYPE = 'BASE TABLE' AND TABLE_NAME <> ?"
+
+	rows, err := m.db.Query(rawSqlString, MigrationsTableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := make(map[string]*snapshotTable)
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, err
+		}
+		tables[table] = &snapshotTable{name: table}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+func (m *MySqlController) getColumns(tables map[string]*snapshotTable) error {
+	/*
+		SELECT TABLE_NAME, COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_DEFAULT, EXTRA
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE()
+		ORDER BY TABLE_NAME, ORDINAL_POSITION;
+	*/
+	const rawSqlString = "SELECT TABLE_NAME, COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_DEFAULT, EXTRA FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() ORDER BY TABLE_NAME, ORDINAL_POSITION"
+
+	rows, err := m.db.Query(rawSqlString)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, columnName, dataType, isNullable, extra string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&tableName, &columnName, &dataType, &isNullable, &defaultValue, &extra); err != nil {
+			return err
+		}
+
+		table, exists := tables[tableName]
+		if !exists {
+			continue
+		}
+
+		column := snapshotColumn{
+			name:          columnName,
+			dataType:      mySqlIntDisplayWidthRegexp.ReplaceAllString(strings.ToLower(dataType), "$1"),
+			nullable:      isNullable == "YES",
+			autoincrement: strings.Contains(strings.ToLower(extra), "auto_increment"),
+		}
+		if defaultValue.Valid {
+			column.defaultValue = m.normalizeDefaultValue(defaultValue.String)
+		}
+
+		table.columns = append(table.columns, column)
+	}
+
+	return rows.Err()
+}
+
+// normalizeReferentialAction treats RESTRICT as NO ACTION, because InnoDB checks both immediately and older versions report the default action as RESTRICT
+func (m *MySqlController) normalizeReferentialAction(action string) string {
+	if action == "RESTRICT" {
+		return ""
+	}
+	return normalizeReferentialAction(action)
+}
+
+func (m *MySqlController) getConstraints(tables map[string]*snapshotTable) error {
+	/*
+		SELECT tc.TABLE_NAME, tc.CONSTRAINT_NAME, tc.CONSTRAINT_TYPE, kcu.COLUMN_NAME, COALESCE(kcu.REFERENCED_TABLE_NAME, ''), COALESCE(kcu.REFERENCED_COLUMN_NAME, ''), COALESCE(rc.DELETE_RULE, ''), COALESCE(rc.UPDATE_RULE, '')
+		FROM information_schema.TABLE_CONSTRAINTS tc
+		JOIN information_schema.KEY_COLUMN_USAGE kcu ON kcu.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA AND kcu.TABLE_NAME = tc.TABLE_NAME AND kcu.CONSTRAINT_NAME = tc.CONSTRAINT_NAME
+		LEFT JOIN information_schema.REFERENTIAL_CONSTRAINTS rc ON rc.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA AND rc.TABLE_NAME = tc.TABLE_NAME AND rc.CONSTRAINT_NAME = tc.CONSTRAINT_NAME
+		WHERE tc.TABLE_SCHEMA = DATABASE() AND tc.CONSTRAINT_TYPE IN ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY')
+		ORDER BY tc.TABLE_NAME, tc.CONSTRAINT_NAME, kcu.ORDINAL_POSITION;
+	*/
+	const rawSqlString = "SELECT tc.TABLE_NAME, tc.CONSTRAINT_NAME, tc.CONSTRAINT_TYPE, kcu.COLUMN_NAME, COALESCE(kcu.REFERENCED_TABLE_NAME, ''), COALESCE(kcu.REFERENCED_COLUMN_NAME, ''), COALESCE(rc.DELETE_RULE, ''), COALESCE(rc.UPDATE_RULE, '') " +
+		"FROM information_schema.TABLE_CONSTRAINTS tc JOIN information_schema.KEY_COLUMN_USAGE kcu ON kcu.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA AND kcu.TABLE_NAME = tc.TABLE_NAME AND kcu.CONSTRAINT_NAME = tc.CONSTRAINT_NAME " +
+		"LEFT JOIN information_schema.REFERENTIAL_CONSTRAINTS rc ON rc.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA AND rc.TABLE_NAME = tc.TABLE_NAME AND rc.CONSTRAINT_NAME = tc.CONSTRAINT_NAME " +
+		"WHERE tc.TABLE_SCHEMA = DATABASE() AND tc.CONSTRAINT_TYPE IN ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY') ORDER BY tc.TABLE_NAME, tc.CONSTRAINT_NAME, kcu.ORDINAL_POSITION"
+
+	rows, err := m.db.Query(rawSqlString)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	lastTableName, lastConstraintName := "", ""
+	for rows.Next() {
+		var tableName, constraintName, constraintType, columnName, referenceTable, referenceColumn, deleteRule, updateRule string
+		if err := rows.Scan(&tableName, &constraintName, &constraintType, &columnName, &referenceTable, &referenceColumn, &deleteRule, &updateRule); err != nil {
+			return err
+		}
+
+		table, exists := tables[tableName]
+		if !exists {
+			continue
+		}
+
+		isNewConstraint := tableName != lastTableName || constraintName != lastConstraintName
+		lastTableName, lastConstraintName = tableName, constraintName
+
+		switch constraintType {
+		case "PRIMARY KEY":
+			// primary key is always named PRIMARY in mysql
+			table.primaryKey.name = fmt.Sprintf("%s_pkey", tableName)
+			table.primaryKey.columns = append(table.primaryKey.columns, columnName)
+		case "UNIQUE":
+			if isNewConstraint {
+				table.uniques = append(table.uniques, snapshotConstraint{name: constraintName})
+			}
+			unique := &table.uniques[len(table.uniques)-1]
+			unique.columns = append(unique.columns, columnName)
+		case "FOREIGN KEY":
+			if isNewConstraint {
+				table.foreignKeys = append(table.foreignKeys, snapshotForeignKey{
+					name:           constraintName,
+					referenceTable: referenceTable,
+					onDelete:       m.normalizeReferentialAction(deleteRule),
+					onUpdate:       m.normalizeReferentialAction(updateRule),
+				})
+			}
+			foreignKey := &table.foreignKeys[len(table.foreignKeys)-1]
+			foreignKey.columns = append(foreignKey.columns, columnName)
+			foreignKey.referenceColumns = append(foreignKey.referenceColumns, referenceColumn)
+		}
+	}
+
+	return rows.Err()
+}
+
+// getIndexes reads indexes that are not primary keys. Mysql creates indexes for foreign keys with names of the constraints,
+// such indexes are skipped. Unique indexes with descending columns are moved from unique constraints to indexes.
+func (m *MySqlController) getIndexes(tables map[string]*snapshotTable) error {
+	/*
+		SELECT TABLE_NAME, INDEX_NAME, NON_UNIQUE, COALESCE(COLUMN_NAME, ''), COALESCE(COLLATION, 'A'), INDEX_TYPE
+		FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = DATABASE() AND INDEX_NAME <> 'PRIMARY'
+		ORDER BY TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX;
+	*/
+	const rawSqlString = "SELECT TABLE_NAME, INDEX_NAME, NON_UNIQUE, COALESCE(COLUMN_NAME, ''), COALESCE(COLLATION, 'A'), INDEX_TYPE " +
+		"FROM information_schema.STATISTICS WHERE TABLE_SCHEMA = DATABASE() AND INDEX_NAME <> 'PRIMARY' ORDER BY TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX"
+
+	rows, err := m.db.Query(rawSqlString)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var columns []mySqlIndexColumn
+	for rows.Next() {
+		var column mySqlIndexColumn
+		if err := rows.Scan(&column.tableName, &column.indexName, &column.nonUnique, &column.columnName, &column.collation, &column.indexType); err != nil {
+			return err
+		}
+		columns = append(columns, column)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	m.addIndexes(tables, columns)
+	return nil
+}
+
+// mySqlIndexColumn is a row of information_schema.STATISTICS
+type mySqlIndexColumn struct {
+	tableName  string
+	indexName  string
+	nonUnique  bool
+	columnName string
+	collation  string
+	indexType  string
+}
+
+// addIndexes groups columns of indexes ordered by table, index and position and adds indexes to the tables
+func (m *MySqlController) addIndexes(tables map[string]*snapshotTable, columns []mySqlIndexColumn) {
+	indexes := make(map[string][]snapshotIndex)
+	lastTableName, lastIndexName := "", ""
+	for _, column := range columns {
+		if column.tableName != lastTableName || column.indexName != lastIndexName {
+			index := snapshotIndex{name: column.indexName, unique: !column.nonUnique}
+			if column.indexType != "BTREE" {
+				index.method = strings.ToLower(column.indexType)
+			}
+			indexes[column.tableName] = append(indexes[column.tableName], index)
+		}
+		lastTableName, lastIndexName = column.tableName, column.indexName
+
+		index := &indexes[column.tableName][len(indexes[column.tableName])-1]
+		index.columns = append(index.columns, column.columnName)
+		if column.collation == "D" {
+			index.descending = append(index.descending, column.columnName)
+		}
+	}
+
+	for tableName, tableIndexes := range indexes {
+		table, exists := tables[tableName]
+		if !exists {
+			continue
+		}
+		for _, index := range tableIndexes {
+			isForeignKeyIndex := slices.ContainsFunc(table.foreignKeys, func(foreignKey snapshotForeignKey) bool { return foreignKey.name == index.name })
+			if isForeignKeyIndex || index.isUniqueConstraint() {
+				continue
+			}
+			if index.unique {
+				table.uniques = slices.DeleteFunc(table.uniques, func(unique snapshotConstraint) bool { return unique.name == index.name })
+			}
+			table.indexes = append(table.indexes, index)
+		}
+	}
+}
+
+// normalizeDefaultValue converts default expression returned by mysql to the form used in snapshots
+func (m *MySqlController) normalizeDefaultValue(expression string) string {
+	if expression == "NULL" {
+		return ""
+	}
+	if strings.HasPrefix(expression, "(") && strings.HasSuffix(expression, ")") {
+		expression = expression[1 : len(expression)-1]
+	}
+	if strings.HasPrefix(expression, "'") && strings.HasSuffix(expression, "'") && len(expression) > 1 {
+		return strings.ReplaceAll(expression[1:len(expression)-1], "''", "'")
+	}
+	return expression
+}
+
+func (m *MySqlController) checkConnection() error {
+	if err := m.db.Ping(); err != nil {
+		return database_error.DatabaseError{
+			ErrorType: database_error.ConnectionError,
+			Text:      fmt.Sprintf("Can't connect to db: %s", err),
+		}
+	}
+	return nil
+}
+
+func (m *MySqlController) generateEnumType(values []string) string {
+	//enum('Admin','User')
+	quotedValues := make([]string, len(values))
+	for index, value := range values {
+		quotedValues[index] = fmt.Sprintf("'%s'", strings.ReplaceAll(value, "'", "''"))
+	}
+	return fmt.Sprintf("enum(%s)", strings.Join(quotedValues, ","))
+}
+
+func (m *MySqlController) generateDefaultValue(column snapshotColumn) string {
+	switch {
+	case column.defaultValue == mySqlCurrentTimestamp:
+		return column.defaultValue
+	case column.defaultValue == mySqlUuidExpression:
+		return fmt.Sprintf("(%s)", column.defaultValue)
+	case strings.HasPrefix(column.dataType, "varchar") || strings.HasPrefix(column.dataType, "enum") || column.dataType == "text" || strings.HasPrefix(column.dataType, "datetime"):
+		return fmt.Sprintf("'%s'", strings.ReplaceAll(column.defaultValue, "'", "''"))
+	default:
+		return column.defaultValue
+	}
+}
+
+func (m *MySqlController) generateColumnDefinition(column snapshotColumn) string {
+	//`id` int NOT NULL AUTO_INCREMENT
+	definition := fmt.Sprintf("`%s` %s", column.name, column.dataType)
+	if !column.nullable {
+		definition += " NOT NULL"
+	}
+	if column.autoincrement {
+		definition += " AUTO_INCREMENT"
+	}
+	if column.defaultValue != "" {
+		definition += fmt.Sprintf(" DEFAULT %s", m.generateDefaultValue(column))
+	}
+	return definition
+}
+
+func (m *MySqlController) generateColumnList(columns []string) string {
+	quotedColumns := make([]string, len(columns))
+	for index, column := range columns {
+		quotedColumns[index] = fmt.Sprintf("`%s`", column)
+	}
+	return strings.Join(quotedColumns, ", ")
+}
+
+func (m *MySqlController) generateCreateTableSqlScriptFromTable(table snapshotTable) string {
+	//CREATE TABLE `User` (`id` int NOT NULL AUTO_INCREMENT, PRIMARY KEY (`id`)) DEFAULT CHARACTER SET utf8mb4;
+	var definitions []string
+	for _, column := range table.columns {
+		definitions = append(definitions, m.generateColumnDefinition(column))
+	}
+	if len(table.primaryKey.columns) != 0 {
+		definitions = append(definitions, fmt.Sprintf("PRIMARY KEY (%s)", m.generateColumnList(table.primaryKey.columns)))
+	}
+	for _, unique := range table.uniques {
+		definitions = append(definitions, fmt.Sprintf("UNIQUE INDEX `%s` (%s)", unique.name, m.generateColumnList(unique.columns)))
+	}
+	return fmt.Sprintf("CREATE TABLE `%s` (\n\t%s\n) DEFAULT CHARACTER SET utf8mb4;", table.name, strings.Join(definitions, ",\n\t"))
+}
+
+func (m *MySqlController) generateDeleteTableSqlScriptFromDbTableName(tableName string) string {
+	//DROP TABLE `User`;
+	return fmt.Sprintf("DROP TABLE `%s`;", tableName)
+}
+
+func (m *MySqlController) generateAddColumnSqlScript(tableName string, column snapshotColumn) string {
+	//ALTER TABLE `User` ADD COLUMN `email` varchar(191) NOT NULL;
+	return fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN %s;", tableName, m.generateColumnDefinition(column))
+}
+
+func (m *MySqlController) generateDropColumnSqlScript(tableName string, columnName string) string {
+	//ALTER TABLE `User` DROP COLUMN `email`;
+	return fmt.Sprintf("ALTER TABLE `%s` DROP COLUMN `%s`;", tableName, columnName)
+}
+
+func (m *MySqlController) generateModifyColumnSqlScript(change columnChange) MigrationStep {
+	//ALTER TABLE `User` MODIFY COLUMN `email` varchar(191) NOT NULL;
+	step := MigrationStep{Query: fmt.Sprintf("ALTER TABLE `%s` MODIFY COLUMN %s;", change.table, m.generateColumnDefinition(change.target))}
+
+	var warnings []string
+	if change.current.dataType != change.target.dataType {
+		warnings = append(warnings, fmt.Sprintf("Type of column \"%s\" of table \"%s\" will be changed from %s to %s. Migration will fail if existing values can't be converted", change.target.name, change.table, change.current.dataType, change.target.dataType))
+	}
+	if change.current.nullable && !change.target.nullable {
+		warnings = append(warnings, fmt.Sprintf("Column \"%s\" of table \"%s\" becomes required. Migration will fail if it contains NULL values", change.target.name, change.table))
+	}
+	step.Warning = strings.Join(warnings, ". ")
+
+	return step
+}
+
+func (m *MySqlController) generateAddIndexSqlScript(tableName string, index snapshotIndex) string {
+	//ALTER TABLE `Post` ADD INDEX `Post_authorId_createdAt_idx` (`authorId`, `createdAt` DESC);
+	columns := make([]string, len(index.columns))
+	for columnIndex, column := range index.columns {
+		columns[columnIndex] = fmt.Sprintf("`%s`", column)
+		if slices.Contains(index.descending, column) {
+			columns[columnIndex] += " DESC"
+		}
+	}
+	indexType := "INDEX"
+	if index.unique {
+		indexType = "UNIQUE INDEX"
+	}
+	return fmt.Sprintf("ALTER TABLE `%s` ADD %s `%s` (%s);", tableName, indexType, index.name, strings.Join(columns, ", "))
+}
+
+func (m *MySqlController) generateDropForeignKeySqlScript(tableName string, foreignKeyName string) string {
+	//ALTER TABLE `Todo` DROP FOREIGN KEY `Todo_userId_fkey`;
+	return fmt.Sprintf("ALTER TABLE `%s` DROP FOREIGN KEY `%s`;", tableName, foreignKeyName)
+}
+
+func (m *MySqlController) generateRelationsSqlScriptFromForeignKey(tableName string, foreignKey snapshotForeignKey) string {
+	//ALTER TABLE `Todo` ADD CONSTRAINT `Todo_userId_fkey` FOREIGN KEY (`userId`) REFERENCES `User` (`id`) ON DELETE CASCADE;
+	return fmt.Sprintf("ALTER TABLE `%s` ADD CONSTRAINT `%s` FOREIGN KEY (%s) REFERENCES `%s` (%s)%s;", tableName, foreignKey.name, m.generateColumnList(foreignKey.columns), foreignKey.referenceTable, m.generateColumnList(foreignKey.referenceColumns), generateReferentialActions(foreignKey))
+}
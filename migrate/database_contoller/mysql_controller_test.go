@@ -0,0 +1,314 @@
+package database_contoller
+
+import (
+	"GoRelCli/models/schema_model"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const mysqlEnumSchema = `
+models:
+  - name: User
+    properties:
+      - name: id
+        type: int
+        default: autoincrement()
+        id: true
+      - name: role
+        type: UserRole
+      - name: previousRole
+        type: UserRole?
+enums:
+  - name: UserRole
+    values: [user, admin, super_user]
+`
+
+const mysqlUuidSchema = `
+models:
+  - name: Note
+    properties:
+      - name: id
+        type: string
+        default: uuid()
+        id: true
+      - name: slug
+        type: string
+        unique: true
+      - name: text
+        type: string
+      - name: title
+        type: string
+        default: untitled
+      - name: createdAt
+        type: dateTime
+        default: now()
+`
+
+const mysqlSelfRelationSchema = `
+models:
+  - name: Employee
+    properties:
+      - name: id
+        type: string
+        default: uuid()
+        id: true
+      - name: managerId
+        type: string?
+      - name: manager
+        type: Employee?
+        relationName: management
+        relationField: managerId
+        referenceField: id
+        onDelete: setNull
+      - name: reports
+        type: Employee[]
+        relationName: management
+`
+
+const mysqlCompositeKeySchema = `
+models:
+  - name: User
+    properties:
+      - name: id
+        type: int
+        default: autoincrement()
+        id: true
+      - name: videos
+        type: UserToVideo[]
+  - name: Video
+    properties:
+      - name: id
+        type: string
+        id: true
+      - name: users
+        type: UserToVideo[]
+  - name: UserToVideo
+    primaryKey: [userId, videoId]
+    properties:
+      - name: userId
+        type: int
+      - name: videoId
+        type: string
+      - name: user
+        type: User
+        relationField: userId
+        referenceField: id
+        onDelete: cascade
+      - name: video
+        type: Video
+        relationField: videoId
+        referenceField: id
+        onDelete: restrict
+        onUpdate: noAction
+`
+
+const mysqlIndexSchema = `
+models:
+  - name: Post
+    properties:
+      - name: id
+        type: int
+        default: autoincrement()
+        id: true
+      - name: authorName
+        type: string
+      - name: title
+        type: string
+      - name: publishedAt
+        type: dateTime?
+    indexes:
+      - fields: [{name: authorName}, {name: title}]
+        unique: true
+      - name: Post_published_idx
+        fields: [{name: publishedAt, sort: desc}, {name: id}]
+`
+
+func TestMySqlInitialMigration(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{name: "enum", schema: mysqlEnumSchema},
+		{name: "uuid_default", schema: mysqlUuidSchema},
+		{name: "self_relation", schema: mysqlSelfRelationSchema},
+		{name: "composite_key", schema: mysqlCompositeKeySchema},
+		{name: "index", schema: mysqlIndexSchema},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			controller, err := NewOfflineDatabaseController(schema_model.MySQL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			schema, enumNames, modelNames := loadTestSchema(t, schema_model.MySQL, test.schema)
+			steps, err := GenerateInitialMigration(controller, schema, enumNames, modelNames)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertGolden(t, filepath.Join("testdata", "mysql", test.name+".sql"), GenerateSqlScript(steps))
+		})
+	}
+}
+
+func TestMySqlMigrationSteps(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		target  string
+	}{
+		{
+			name:    "enum_values",
+			current: mysqlEnumSchema,
+			target: `
+models:
+  - name: User
+    properties:
+      - name: id
+        type: int
+        default: autoincrement()
+        id: true
+      - name: role
+        type: UserRole
+      - name: previousRole
+        type: UserRole?
+enums:
+  - name: UserRole
+    values: [user, moderator, admin, super_user]
+`,
+		},
+		{
+			name:    "retyped_key",
+			current: mysqlCompositeKeySchema,
+			target: `
+models:
+  - name: User
+    properties:
+      - name: id
+        type: int
+        default: autoincrement()
+        id: true
+      - name: videos
+        type: UserToVideo[]
+  - name: Video
+    properties:
+      - name: id
+        type: int
+        id: true
+      - name: users
+        type: UserToVideo[]
+  - name: UserToVideo
+    primaryKey: [userId, videoId]
+    properties:
+      - name: userId
+        type: int
+      - name: videoId
+        type: int
+      - name: user
+        type: User
+        relationField: userId
+        referenceField: id
+        onDelete: cascade
+      - name: video
+        type: Video
+        relationField: videoId
+        referenceField: id
+        onDelete: cascade
+`,
+		},
+		{
+			name:    "index",
+			current: mysqlIndexSchema,
+			target: `
+models:
+  - name: Post
+    properties:
+      - name: id
+        type: int
+        default: autoincrement()
+        id: true
+      - name: authorName
+        type: string
+      - name: title
+        type: string?
+      - name: publishedAt
+        type: dateTime?
+    indexes:
+      - fields: [{name: authorName}]
+      - name: Post_published_idx
+        fields: [{name: publishedAt}, {name: id}]
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			controller, current := testSnapshot(t, schema_model.MySQL, test.current)
+			_, target := testSnapshot(t, schema_model.MySQL, test.target)
+			steps := controller.generateMigrationSteps(diffSnapshots(current, target))
+			assertGolden(t, filepath.Join("testdata", "mysql", test.name+"_migration.sql"), GenerateSqlScript(steps))
+		})
+	}
+}
+
+func TestMySqlIndexRoundTrip(t *testing.T) {
+	controller, target := testSnapshot(t, schema_model.MySQL, `
+models:
+  - name: Author
+    properties:
+      - name: id
+        type: int
+        default: autoincrement()
+        id: true
+      - name: posts
+        type: Post[]
+  - name: Post
+    properties:
+      - name: id
+        type: int
+        default: autoincrement()
+        id: true
+      - name: authorId
+        type: int
+      - name: author
+        type: Author
+        relationField: authorId
+        referenceField: id
+      - name: authorName
+        type: string
+      - name: title
+        type: string
+      - name: publishedAt
+        type: dateTime?
+    indexes:
+      - fields: [{name: authorName}, {name: title}]
+        unique: true
+      - fields: [{name: title}]
+      - name: Post_published_idx
+        fields: [{name: publishedAt, sort: desc}, {name: id}]
+      - name: Post_title_desc_key
+        fields: [{name: title, sort: desc}]
+        unique: true
+`)
+	post, _ := target.findTable("Post")
+
+	// every unique index is a unique constraint in information_schema.TABLE_CONSTRAINTS,
+	// information_schema.STATISTICS contains indexes of unique constraints and foreign keys too
+	introspected := replaceIndexes(target, "Post", append(slices.Clone(post.uniques), snapshotConstraint{name: "Post_title_desc_key", columns: []string{"title"}}), nil)
+	tables := make(map[string]*snapshotTable)
+	for tableIndex := range introspected.tables {
+		tables[introspected.tables[tableIndex].name] = &introspected.tables[tableIndex]
+	}
+	controller.(*MySqlController).addIndexes(tables, []mySqlIndexColumn{
+		{tableName: "Post", indexName: "Post_authorId_fkey", nonUnique: true, columnName: "authorId", collation: "A", indexType: "BTREE"},
+		{tableName: "Post", indexName: "Post_authorName_title_key", columnName: "authorName", collation: "A", indexType: "BTREE"},
+		{tableName: "Post", indexName: "Post_authorName_title_key", columnName: "title", collation: "A", indexType: "BTREE"},
+		{tableName: "Post", indexName: "Post_published_idx", nonUnique: true, columnName: "publishedAt", collation: "D", indexType: "BTREE"},
+		{tableName: "Post", indexName: "Post_published_idx", nonUnique: true, columnName: "id", collation: "A", indexType: "BTREE"},
+		{tableName: "Post", indexName: "Post_title_desc_key", columnName: "title", collation: "D", indexType: "BTREE"},
+		{tableName: "Post", indexName: "Post_title_idx", nonUnique: true, columnName: "title", collation: "A", indexType: "BTREE"},
+	})
+
+	assertRoundTrip(t, controller, schema_model.MySQL, target, introspected)
+}
@@ -4,243 +4,485 @@ import (
 	"GoRelCli/models/error_model/database_error"
 	"GoRelCli/models/schema_model"
 	"database/sql"
-	"errors"
 	"fmt"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 )
 
-type databaseEnum struct {
-	Oid      int
-	TypeName string
+type PostgresController struct {
+	db *sql.DB
 }
 
-type relationType string
+// postgresAddEnumValueRegexp matches statements that add values to enums. They are executed before transaction of migration,
+// because values added inside of transaction can't be used until it is committed (and older versions reject them).
+var postgresAddEnumValueRegexp = regexp.MustCompile(`^ALTER TYPE "[^"]+" ADD VALUE .*;$`)
 
-const (
-	OneToOne   relationType = "OneToOne"
-	OneToMany               = "OneToMany"
-	ManyToMany              = "ManyToMany"
-)
+// postgresCastRegexp matches type cast at the end of default value expression (e.g. 'Admin'::"UserRole")
+var postgresCastRegexp = regexp.MustCompile(`^(.*)::[a-zA-Z_" ]+(\[\])?$`)
+
+func (p *PostgresController) createSnapshotColumn(property schema_model.Property, enumNames []string) (snapshotColumn, error) {
+	column := snapshotColumn{name: property.Name}
+
+	propertyType := property.Type
+	isOptional := strings.HasSuffix(propertyType, "?")
+	if isOptional {
+		propertyType = propertyType[0 : len(propertyType)-1]
+	}
+
+	if slices.Contains(enumNames, propertyType) {
+		column.dataType = propertyType
+		column.enumName = propertyType
+		column.nullable = isOptional
+	} else {
+		postgresType, isValidType := property.GetPostgresType()
+		if !isValidType {
+			return snapshotColumn{}, database_error.DatabaseError{
+				ErrorType: database_error.SqlGenerationError,
+				Text:      fmt.Sprintf("Invalid property type provided: %s", property.Type),
+			}
+		}
+		column.dataType = strings.TrimSuffix(postgresType, " NOT NULL")
+		column.nullable = !strings.HasSuffix(postgresType, " NOT NULL")
+	}
+
+	switch property.Default {
+	case "":
+	case "autoincrement()":
+		column.autoincrement = true
+		column.nullable = false
+	case "uuid()":
+		column.dataType = "uuid"
+		column.defaultValue = "gen_random_uuid()"
+	case "now()":
+		column.defaultValue = "now()"
+	default:
+		value, err := property.ValidateDefaultValue()
+		if err != nil {
+			return snapshotColumn{}, err
+		}
+		if boolValue, isBool := value.(bool); isBool {
+			column.defaultValue = strconv.FormatBool(boolValue)
+		} else {
+			column.defaultValue = property.Default
+		}
+	}
 
-type Relation struct {
-	relationType       relationType
-	referenceModelName string
-	referenceFieldName string
-	relationModelName  string
-	relationFieldName  string
+	return column, nil
 }
 
-type PostgresController struct {
-	db *sql.DB
+func (p *PostgresController) createSnapshot(schema *schema_model.GoRelSchema, enumNames []string, modelNames []string) (databaseSnapshot, error) {
+	return createSnapshotFromSchema(schema, enumNames, modelNames, p.createSnapshotColumn)
 }
 
-func (p *PostgresController) dropTables() error {
-	tableNames, err := p.getTables()
+// postgresTypeModifierRegexp matches length and precision of the type (e.g. character varying(255))
+var postgresTypeModifierRegexp = regexp.MustCompile(`\([0-9, ]+\)`)
+
+// postgresTypeAliases contains postgres types that are not created by GoRel, but can be described by the closest schema type
+var postgresTypeAliases = map[string]string{
+	"smallint":                    "integer",
+	"bigint":                      "integer",
+	"real":                        "double precision",
+	"numeric":                     "double precision",
+	"character varying":           "text",
+	"character":                   "text",
+	"uuid":                        "text",
+	"timestamp without time zone": "timestamp with time zone",
+	"date":                        "timestamp with time zone",
+}
 
-	if err != nil {
-		return database_error.DatabaseError{
-			ErrorType: database_error.TransactionError,
-			Text:      fmt.Sprintf("Can't get table names: %s", err),
+func (p *PostgresController) createSchemaProperty(tableName string, column snapshotColumn) (schema_model.Property, *snapshotEnum, []string) {
+	property := schema_model.Property{Name: column.name}
+	var warnings []string
+
+	if column.enumName != "" {
+		property.Type = column.enumName
+		if column.nullable {
+			property.Type += "?"
+		}
+	} else {
+		dataType := postgresTypeModifierRegexp.ReplaceAllString(column.dataType, "")
+		isArray := strings.HasSuffix(dataType, "[]")
+		dataType = strings.TrimSuffix(dataType, "[]")
+		alias, isAlias := postgresTypeAliases[dataType]
+		if isAlias {
+			dataType = alias
+		}
+		if isArray {
+			dataType += "[]"
+		}
+		// uuid with generated default is exactly what uuid() creates
+		if isAlias && !(column.dataType == "uuid" && column.defaultValue == "gen_random_uuid()") {
+			warnings = append(warnings, fmt.Sprintf("Type %s of column \"%s\" of table \"%s\" is not supported by the schema and is replaced with %s", column.dataType, column.name, tableName, dataType))
+		}
+
+		// Arrays are always nullable in the schema
+		propertyType, isFound := schema_model.PropertyTypeFromPostgresType(dataType, column.nullable || isArray)
+		if !isFound {
+			propertyType = schema_model.String
+			if column.nullable {
+				propertyType = schema_model.StringNullable
+			}
+			warnings = append(warnings, fmt.Sprintf("Type %s of column \"%s\" of table \"%s\" is not supported by the schema and is replaced with %s", column.dataType, column.name, tableName, propertyType))
 		}
+		property.Type = string(propertyType)
 	}
 
-	if len(tableNames) == 0 {
-		fmt.Println("No tables found. Skipping drop tables...")
-		return nil
+	defaultValue := column.defaultValue
+	switch {
+	case column.autoincrement:
+		defaultValue = "autoincrement()"
+	case defaultValue == "gen_random_uuid()":
+		defaultValue = "uuid()"
+	case defaultValue == "now()" || defaultValue == "CURRENT_TIMESTAMP":
+		defaultValue = "now()"
 	}
+	warnings = append(warnings, setPropertyDefault(&property, tableName, defaultValue)...)
+
+	return property, nil, warnings
+}
+
+func (p *PostgresController) getSnapshot() (databaseSnapshot, error) {
+	var snapshot databaseSnapshot
 
-	var queries []string
-	for _, tableName := range tableNames {
-		query := p.generateDeleteTableSqlScriptFromDbTableName(tableName)
-		queries = append(queries, query)
+	enums, err := p.getEnums()
+	if err != nil {
+		return databaseSnapshot{}, database_error.DatabaseError{
+			ErrorType: database_error.IntrospectionError,
+			Text:      fmt.Sprintf("Can't get enums: %s", err),
+		}
 	}
-	rawSqlString := p.generateTransaction(queries)
-	_, err = p.db.Exec(rawSqlString)
+	snapshot.enums = enums
 
+	tables, err := p.getTables()
 	if err != nil {
-		return database_error.DatabaseError{
-			ErrorType: database_error.TransactionError,
-			Text:      fmt.Sprintf("Can't drop tables: %s", err),
+		return databaseSnapshot{}, database_error.DatabaseError{
+			ErrorType: database_error.IntrospectionError,
+			Text:      fmt.Sprintf("Can't get tables: %s", err),
 		}
 	}
 
-	return nil
+	if err := p.getColumns(tables); err != nil {
+		return databaseSnapshot{}, database_error.DatabaseError{
+			ErrorType: database_error.IntrospectionError,
+			Text:      fmt.Sprintf("Can't get columns: %s", err),
+		}
+	}
+
+	if err := p.getConstraints(tables); err != nil {
+		return databaseSnapshot{}, database_error.DatabaseError{
+			ErrorType: database_error.IntrospectionError,
+			Text:      fmt.Sprintf("Can't get constraints: %s", err),
+		}
+	}
+
+	if err := p.getIndexes(tables); err != nil {
+		return databaseSnapshot{}, database_error.DatabaseError{
+			ErrorType: database_error.IntrospectionError,
+			Text:      fmt.Sprintf("Can't get indexes: %s", err),
+		}
+	}
+
+	for _, tableName := range sortedKeys(tables) {
+		snapshot.tables = append(snapshot.tables, *tables[tableName])
+	}
+
+	return snapshot, nil
 }
 
-func (p *PostgresController) dropEnums() error {
-	dbEnums, err := p.getEnums()
+func (p *PostgresController) generateMigrationSteps(diff snapshotDiff) []MigrationStep {
+	var steps []MigrationStep
 
-	if err != nil {
-		return database_error.DatabaseError{
-			ErrorType: database_error.TransactionError,
-			Text:      fmt.Sprintf("Can't get enum names: %s", err),
+	for _, foreignKey := range diff.droppedForeignKeys {
+		steps = append(steps, MigrationStep{Query: p.generateDropConstraintSqlScript(foreignKey.table, foreignKey.foreignKey.name)})
+	}
+
+	for _, primaryKey := range diff.droppedPrimaryKeys {
+		steps = append(steps, MigrationStep{Query: p.generateDropConstraintSqlScript(primaryKey.table, primaryKey.constraint.name)})
+	}
+
+	for _, unique := range diff.droppedUniques {
+		steps = append(steps, MigrationStep{Query: p.generateDropConstraintSqlScript(unique.table, unique.constraint.name)})
+	}
+
+	for _, index := range diff.droppedIndexes {
+		steps = append(steps, MigrationStep{Query: p.generateDropIndexSqlScript(index.index.name)})
+	}
+
+	for _, table := range diff.droppedTables {
+		steps = append(steps, MigrationStep{
+			Query:   p.generateDeleteTableSqlScriptFromDbTableName(table.name),
+			Warning: fmt.Sprintf("Table \"%s\" will be dropped with all of its data", table.name),
+		})
+	}
+
+	for _, column := range diff.droppedColumns {
+		steps = append(steps, MigrationStep{
+			Query:   p.generateDropColumnSqlScript(column.table, column.column.name),
+			Warning: fmt.Sprintf("Column \"%s\" of table \"%s\" will be dropped with all of its data", column.column.name, column.table),
+		})
+	}
+
+	for _, enum := range diff.createdEnums {
+		steps = append(steps, MigrationStep{Query: p.generateCreateEnumSqlScriptFromEnum(enum)})
+	}
+
+	for _, change := range diff.alteredEnums {
+		steps = append(steps, p.generateAlterEnumSqlScripts(change, diff)...)
+	}
+
+	for _, table := range diff.createdTables {
+		steps = append(steps, MigrationStep{Query: p.generateCreateTableSqlScriptFromTable(table)})
+	}
+
+	for _, column := range diff.addedColumns {
+		step := MigrationStep{Query: p.generateAddColumnSqlScript(column.table, column.column)}
+		if !column.column.nullable && column.column.defaultValue == "" && !column.column.autoincrement {
+			step.Warning = fmt.Sprintf("Required column \"%s\" without default value is added to table \"%s\". Migration will fail if the table is not empty", column.column.name, column.table)
 		}
+		steps = append(steps, step)
+	}
+
+	for _, change := range diff.alteredColumns {
+		steps = append(steps, p.generateAlterColumnSqlScripts(change)...)
+	}
+
+	for _, primaryKey := range diff.addedPrimaryKeys {
+		steps = append(steps, MigrationStep{Query: p.generateAddPrimaryKeySqlScript(primaryKey.table, primaryKey.constraint)})
 	}
 
-	if len(dbEnums) == 0 {
-		fmt.Println("No enums found. Skipping drop enums...")
-		return nil
+	for _, unique := range diff.addedUniques {
+		steps = append(steps, MigrationStep{
+			Query:   p.generateAddUniqueSqlScript(unique.table, unique.constraint),
+			Warning: fmt.Sprintf("Unique constraint \"%s\" is added to table \"%s\". Migration will fail if there are duplicate values", unique.constraint.name, unique.table),
+		})
 	}
 
-	var queries []string
-	for _, dbEnum := range dbEnums {
-		queries = append(queries, p.generateDeleteEnumSqlScriptFromDbEnum(dbEnum))
+	for _, index := range diff.addedIndexes {
+		step := MigrationStep{Query: p.generateCreateIndexSqlScript(index.table, index.index)}
+		if index.index.unique {
+			step.Warning = fmt.Sprintf("Unique index \"%s\" is added to table \"%s\". Migration will fail if there are duplicate values", index.index.name, index.table)
+		}
+		steps = append(steps, step)
 	}
 
-	rawSqlQuery := p.generateTransaction(queries)
+	for _, foreignKey := range diff.addedForeignKeys {
+		steps = append(steps, MigrationStep{Query: p.generateRelationsSqlScriptFromForeignKey(foreignKey.table, foreignKey.foreignKey)})
+	}
+
+	for _, enum := range diff.droppedEnums {
+		steps = append(steps, MigrationStep{Query: p.generateDeleteEnumSqlScriptFromEnum(enum)})
+	}
 
-	if _, err = p.db.Exec(rawSqlQuery); err != nil {
+	return steps
+}
+
+func (p *PostgresController) createMigrationsTable() error {
+	/*
+		CREATE TABLE IF NOT EXISTS "_gorel_migrations" (
+			"name" text NOT NULL,
+			"checksum" text NOT NULL,
+			"started_at" timestamp with time zone NOT NULL DEFAULT(now()),
+			"finished_at" timestamp with time zone,
+			"failed" boolean NOT NULL DEFAULT(false),
+			CONSTRAINT "_gorel_migrations_pkey" PRIMARY KEY ("name")
+		);
+	*/
+	rawSqlString := fmt.Sprintf("CREATE TABLE IF NOT EXISTS \"%s\" (\"name\" text NOT NULL, \"checksum\" text NOT NULL, \"started_at\" timestamp with time zone NOT NULL DEFAULT(now()), \"finished_at\" timestamp with time zone, \"failed\" boolean NOT NULL DEFAULT(false), CONSTRAINT \"%s_pkey\" PRIMARY KEY (\"name\"));", MigrationsTableName, MigrationsTableName)
+	if _, err := p.db.Exec(rawSqlString); err != nil {
 		return database_error.DatabaseError{
 			ErrorType: database_error.TransactionError,
-			Text:      fmt.Sprintf("Can't drop enums: %s", err),
+			Text:      fmt.Sprintf("Can't create migrations table: %s", err),
 		}
 	}
-
 	return nil
 }
 
-func (p *PostgresController) defineRelation(relationModel schema_model.Model, models []schema_model.Model, propertyIndex int) (Relation, error) {
-	relation := Relation{
-		relationModelName: relationModel.Name,
+func (p *PostgresController) migrationsTableExists() (bool, error) {
+	var exists bool
+	if err := p.db.QueryRow("SELECT to_regclass($1) IS NOT NULL", fmt.Sprintf("public.\"%s\"", MigrationsTableName)).Scan(&exists); err != nil {
+		return false, database_error.DatabaseError{
+			ErrorType: database_error.IntrospectionError,
+			Text:      fmt.Sprintf("Can't check if migrations table exists: %s", err),
+		}
 	}
+	return exists, nil
+}
 
-	relationType := relationModel.Properties[propertyIndex].Type
-	relationFieldName := relationModel.Properties[propertyIndex].RelationField
-	referenceFieldName := relationModel.Properties[propertyIndex].ReferenceField
-
-	relation.relationFieldName = relationFieldName
-	relation.referenceFieldName = referenceFieldName
-
-	referenceModelName := relationType
-	if strings.Contains(referenceModelName, "[]") {
-		referenceModelName = referenceModelName[0 : len(referenceModelName)-2]
+// GetAppliedMigrations returns records of the migrations table. It does not create the table, so database is not changed.
+func (p *PostgresController) GetAppliedMigrations() ([]AppliedMigration, error) {
+	exists, err := p.migrationsTableExists()
+	if err != nil {
+		return nil, err
 	}
-	if strings.Contains(referenceModelName, "?") {
-		referenceModelName = referenceModelName[0 : len(referenceModelName)-1]
+	if !exists {
+		return nil, nil
 	}
 
-	referenceType := ""
+	rows, err := p.db.Query(fmt.Sprintf("SELECT \"name\", \"checksum\", \"started_at\", \"finished_at\", \"failed\" FROM \"%s\" ORDER BY \"name\"", MigrationsTableName))
+	if err != nil {
+		return nil, database_error.DatabaseError{
+			ErrorType: database_error.IntrospectionError,
+			Text:      fmt.Sprintf("Can't get applied migrations: %s", err),
+		}
+	}
+	defer rows.Close()
 
-	for _, model := range models {
-		if model.Name == referenceModelName {
-			relation.referenceModelName = model.Name
-			for _, property := range model.Properties {
-				propertyType := property.Type
-				if strings.Contains(propertyType, "?") {
-					propertyType = propertyType[0 : len(propertyType)-1]
-				}
-				if strings.Contains(propertyType, "[]") {
-					propertyType = propertyType[0 : len(propertyType)-2]
-				}
-				if propertyType == relationModel.Name {
-					referenceType = property.Type
-					break
-				}
+	var migrations []AppliedMigration
+	for rows.Next() {
+		var migration AppliedMigration
+		if err := rows.Scan(&migration.Name, &migration.Checksum, &migration.StartedAt, &migration.FinishedAt, &migration.Failed); err != nil {
+			return nil, database_error.DatabaseError{
+				ErrorType: database_error.IntrospectionError,
+				Text:      fmt.Sprintf("Can't get applied migrations: %s", err),
 			}
-			break
 		}
+		migrations = append(migrations, migration)
 	}
 
-	if referenceType == "" {
-		return Relation{}, database_error.DatabaseError{
-			ErrorType: database_error.SqlGenerationError,
-			Text:      "ReferenceField not found",
+	if err := rows.Err(); err != nil {
+		return nil, database_error.DatabaseError{
+			ErrorType: database_error.IntrospectionError,
+			Text:      fmt.Sprintf("Can't get applied migrations: %s", err),
 		}
 	}
 
-	isReferenceTypeArray := strings.Contains(referenceType, "[]")
-	isRelationTypeArray := strings.Contains(relationType, "[]")
+	return migrations, nil
+}
 
-	fmt.Println(fmt.Sprintf("Relation type: %s, reference type: %s", relationType, referenceType))
+// splitAddEnumValueStatements moves lines that add enum values out of the script
+func splitAddEnumValueStatements(script string) (enumStatements []string, rest string) {
+	var lines []string
+	for _, line := range strings.Split(script, "\n") {
+		if postgresAddEnumValueRegexp.MatchString(strings.TrimSpace(line)) {
+			enumStatements = append(enumStatements, strings.TrimSpace(line))
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return enumStatements, strings.Join(lines, "\n")
+}
 
-	if isReferenceTypeArray && isRelationTypeArray {
-		relation.relationType = ManyToMany
-		return relation, nil
+// addEnumValues runs ALTER TYPE ... ADD VALUE statements outside of transaction. Added values are kept if the rest of migration fails,
+// statements use IF NOT EXISTS, so the migration can be applied again.
+func (p *PostgresController) addEnumValues(statements []string) error {
+	for _, statement := range statements {
+		if _, err := p.db.Exec(statement); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	if !isReferenceTypeArray && !isRelationTypeArray {
-		relation.relationType = OneToOne
-		return relation, nil
+func (p *PostgresController) markMigrationAsFailed(name string) {
+	if _, err := p.db.Exec(fmt.Sprintf("UPDATE \"%s\" SET \"failed\" = true, \"finished_at\" = now() WHERE \"name\" = $1", MigrationsTableName), name); err != nil {
+		fmt.Println(fmt.Sprintf("Can't mark migration %s as failed: %s", name, err))
 	}
+}
 
-	if isReferenceTypeArray && !isRelationTypeArray {
-		relation.relationType = OneToMany
-		return relation, nil
+func (p *PostgresController) ApplyMigration(name string, checksum string, script string) error {
+	if err := p.createMigrationsTable(); err != nil {
+		return err
 	}
 
-	return Relation{}, database_error.DatabaseError{
-		ErrorType: database_error.SqlGenerationError,
-		Text:      "RelationField is array type, but ReferenceField is not",
+	// Migration record is saved outside of transaction, so it is kept if migration fails
+	startMigrationQuery := fmt.Sprintf("INSERT INTO \"%s\" (\"name\", \"checksum\") VALUES ($1, $2) ON CONFLICT (\"name\") DO UPDATE SET \"checksum\" = $2, \"started_at\" = now(), \"finished_at\" = NULL, \"failed\" = false", MigrationsTableName)
+	if _, err := p.db.Exec(startMigrationQuery, name, checksum); err != nil {
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't save migration %s: %s", name, err),
+		}
 	}
-}
 
-func (p *PostgresController) createTables(enumNames []string, modelNames []string, models []schema_model.Model) error {
-	var createTableQueries []string
-	var createRelationsQueries []string
-	for _, model := range models {
-		err := p.generateCreateTableWithoutRelationsSqlScriptFromModel(model, models, enumNames, modelNames, &createTableQueries, &createRelationsQueries)
-		if err != nil {
-			return database_error.DatabaseError{
-				ErrorType: database_error.SqlGenerationError,
-				Text:      fmt.Sprintf("Can't generate sql query for relations and tables creation: %s", err),
-			}
+	enumStatements, script := splitAddEnumValueStatements(script)
+	if err := p.addEnumValues(enumStatements); err != nil {
+		p.markMigrationAsFailed(name)
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't apply migration %s: %s", name, err),
 		}
 	}
 
-	createTablesRawSQLQuery := p.generateTransaction(createTableQueries)
-	createRelationsRawSQLQuery := p.generateTransaction(createRelationsQueries)
+	tx, err := p.db.Begin()
+	if err != nil {
+		p.markMigrationAsFailed(name)
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't start transaction for migration %s: %s", name, err),
+		}
+	}
 
-	fmt.Println(fmt.Sprintf("Generated query for creating tables:\n%s", createTablesRawSQLQuery))
-	fmt.Println(fmt.Sprintf("Generated query for creating relations:\n%s", createRelationsRawSQLQuery))
+	if _, err := tx.Exec(script); err != nil {
+		_ = tx.Rollback()
+		p.markMigrationAsFailed(name)
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't apply migration %s: %s", name, err),
+		}
+	}
 
-	if _, err := p.db.Exec(createTablesRawSQLQuery); err != nil {
+	if _, err := tx.Exec(fmt.Sprintf("UPDATE \"%s\" SET \"finished_at\" = now() WHERE \"name\" = $1", MigrationsTableName), name); err != nil {
+		_ = tx.Rollback()
+		p.markMigrationAsFailed(name)
 		return database_error.DatabaseError{
 			ErrorType: database_error.TransactionError,
-			Text:      fmt.Sprintf("Can't create tables: %s", err),
+			Text:      fmt.Sprintf("Can't save migration %s: %s", name, err),
 		}
 	}
 
-	if _, err := p.db.Exec(createRelationsRawSQLQuery); err != nil {
+	if err := tx.Commit(); err != nil {
+		p.markMigrationAsFailed(name)
 		return database_error.DatabaseError{
 			ErrorType: database_error.TransactionError,
-			Text:      fmt.Sprintf("Can't create relations: %s", err),
+			Text:      fmt.Sprintf("Can't commit migration %s: %s", name, err),
 		}
 	}
 
 	return nil
 }
 
-func (p *PostgresController) createEnums(enums []schema_model.Enum) error {
-	var queries []string
-	for _, enum := range enums {
-		queries = append(queries, p.generateCreateEnumSqlScriptFromEnum(enum))
-	}
-	rawSqlQuery := p.generateTransaction(queries)
-	fmt.Println(fmt.Sprintf("Generated query for creating enums:\n%s", rawSqlQuery))
-	if _, err := p.db.Exec(rawSqlQuery); err != nil {
+func (p *PostgresController) RollbackMigration(name string, script string) error {
+	enumStatements, script := splitAddEnumValueStatements(script)
+	if err := p.addEnumValues(enumStatements); err != nil {
 		return database_error.DatabaseError{
 			ErrorType: database_error.TransactionError,
-			Text:      fmt.Sprintf("Can't create enums: %s", err),
+			Text:      fmt.Sprintf("Can't rollback migration %s: %s", name, err),
 		}
 	}
-	return nil
-}
 
-func (p *PostgresController) RunMigrations(schema *schema_model.GoRelSchema, enumNames []string, modelNames []string) error {
-	if err := p.dropTables(); err != nil {
-		return err
+	tx, err := p.db.Begin()
+	if err != nil {
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't start transaction for rollback of migration %s: %s", name, err),
+		}
 	}
-	if err := p.dropEnums(); err != nil {
-		return err
+
+	if _, err := tx.Exec(script); err != nil {
+		_ = tx.Rollback()
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't rollback migration %s: %s", name, err),
+		}
 	}
-	if err := p.createEnums(schema.Enums); err != nil {
-		return err
+
+	if _, err := tx.Exec(fmt.Sprintf("DELETE FROM \"%s\" WHERE \"name\" = $1", MigrationsTableName), name); err != nil {
+		_ = tx.Rollback()
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't delete migration %s from migrations table: %s", name, err),
+		}
 	}
-	if err := p.createTables(enumNames, modelNames, schema.Models); err != nil {
-		return err
+
+	if err := tx.Commit(); err != nil {
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't commit rollback of migration %s: %s", name, err),
+		}
 	}
+
 	return nil
 }
 
@@ -254,35 +496,33 @@ func (p *PostgresController) Close() error {
 	return nil
 }
 
-func (p *PostgresController) generateTransaction(sqlQueries []string) string {
-	sqlString := "BEGIN;\n"
-	for _, sqlQuery := range sqlQueries {
-		sqlString = sqlString + sqlQuery + "\n"
-	}
-	sqlString = sqlString + "COMMIT;"
-	return sqlString
-}
-
-func (p *PostgresController) getEnums() ([]databaseEnum, error) {
+func (p *PostgresController) getEnums() ([]snapshotEnum, error) {
 	/*
-		select OID
-		FROM pg_type
-		WHERE OID = ANY (select enumtypid FROM pg_enum);
+		SELECT t.typname, e.enumlabel
+		FROM pg_type t
+		JOIN pg_enum e ON e.enumtypid = t.oid
+		JOIN pg_namespace n ON n.oid = t.typnamespace
+		WHERE n.nspname = 'public'
+		ORDER BY t.typname, e.enumsortorder;
 	*/
-	const rawSqlString = "SELECT OID,TYPNAME FROM pg_type WHERE OID = ANY(SELECT enumtypid FROM pg_enum)"
+	const rawSqlString = "SELECT t.typname, e.enumlabel FROM pg_type t JOIN pg_enum e ON e.enumtypid = t.oid JOIN pg_namespace n ON n.oid = t.typnamespace WHERE n.nspname = 'public' ORDER BY t.typname, e.enumsortorder"
 
 	rows, err := p.db.Query(rawSqlString)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	var enums []databaseEnum
+	var enums []snapshotEnum
 	for rows.Next() {
-		enum := databaseEnum{}
-		if err := rows.Scan(&enum.Oid, &enum.TypeName); err != nil {
+		var enumName, value string
+		if err := rows.Scan(&enumName, &value); err != nil {
 			return nil, err
 		}
-		enums = append(enums, enum)
+		if len(enums) == 0 || enums[len(enums)-1].name != enumName {
+			enums = append(enums, snapshotEnum{name: enumName})
+		}
+		enums[len(enums)-1].values = append(enums[len(enums)-1].values, value)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -292,26 +532,27 @@ func (p *PostgresController) getEnums() ([]databaseEnum, error) {
 	return enums, nil
 }
 
-func (p *PostgresController) getTables() ([]string, error) {
+func (p *PostgresController) getTables() (map[string]*snapshotTable, error) {
 	/*
 		SELECT table_name
 		FROM information_schema.tables
-		WHERE table_schema = 'public';
+		WHERE table_schema = 'public' AND table_type = 'BASE TABLE' AND table_name <> '_gorel_migrations';
 	*/
-	const rawSqlString = "select table_name from information_schema.tables where table_schema = 'public'"
+	const rawSqlString = "select table_name from information_schema.tables where table_schema = 'public' and table_type = 'BASE TABLE' and table_name <> $1"
 
-	rows, err := p.db.Query(rawSqlString)
+	rows, err := p.db.Query(rawSqlString, MigrationsTableName)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	var tables []string
+	tables := make(map[string]*snapshotTable)
 	for rows.Next() {
 		var table string
 		if err := rows.Scan(&table); err != nil {
 			return nil, err
 		}
-		tables = append(tables, table)
+		tables[table] = &snapshotTable{name: table}
 	}
 
 	if err := rows.Err(); err != nil {
@@ -321,160 +562,432 @@ func (p *PostgresController) getTables() ([]string, error) {
 	return tables, nil
 }
 
-func (p *PostgresController) checkConnection() error {
-	if err := p.db.Ping(); err != nil {
-		return database_error.DatabaseError{
-			ErrorType: database_error.ConnectionError,
-			Text:      fmt.Sprintf("Can't connect to db: %s", err),
+func (p *PostgresController) getColumns(tables map[string]*snapshotTable) error {
+	/*
+		SELECT c.relname, a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull, COALESCE(pg_get_expr(d.adbin, d.adrelid), ''), t.typtype = 'e'
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_type t ON t.oid = a.atttypid
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE n.nspname = 'public' AND c.relkind = 'r' AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY c.relname, a.attnum;
+	*/
+	const rawSqlString = "SELECT c.relname, a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull, COALESCE(pg_get_expr(d.adbin, d.adrelid), ''), t.typtype = 'e' FROM pg_attribute a JOIN pg_class c ON c.oid = a.attrelid JOIN pg_namespace n ON n.oid = c.relnamespace JOIN pg_type t ON t.oid = a.atttypid LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum WHERE n.nspname = 'public' AND c.relkind = 'r' AND a.attnum > 0 AND NOT a.attisdropped ORDER BY c.relname, a.attnum"
+
+	rows, err := p.db.Query(rawSqlString)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, columnName, dataType, defaultValue string
+		var notNull, isEnum bool
+		if err := rows.Scan(&tableName, &columnName, &dataType, &notNull, &defaultValue, &isEnum); err != nil {
+			return err
+		}
+
+		table, exists := tables[tableName]
+		if !exists {
+			continue
+		}
+
+		column := snapshotColumn{
+			name:     columnName,
+			dataType: strings.ReplaceAll(dataType, "\"", ""),
+			nullable: !notNull,
+		}
+		if isEnum {
+			column.enumName = column.dataType
 		}
+		column.defaultValue, column.autoincrement = p.normalizeDefaultValue(defaultValue)
+
+		table.columns = append(table.columns, column)
 	}
-	return nil
+
+	return rows.Err()
 }
 
-func (p *PostgresController) generateDeleteTableSqlScriptFromDbTableName(tableName string) string {
-	//DROP TABLE User CASCADE;
-	return fmt.Sprintf("DROP TABLE \"%s\" CASCADE;", tableName)
+// postgresReferentialActions maps codes of referential actions in pg_constraint to sql, NO ACTION (a) is the default
+var postgresReferentialActions = map[string]string{
+	"r": "RESTRICT",
+	"c": "CASCADE",
+	"n": "SET NULL",
+	"d": "SET DEFAULT",
 }
 
-func (p *PostgresController) generateRelationsSqlScriptFromProperty(relation Relation) string {
-	//ALTER TABLE "Todo" ADD CONSTRAINT "fk_User" FOREIGN KEY ("userId") REFERENCES "User" ("id");
-	relationTableName := relation.relationModelName
-	referenceTableName := relation.referenceModelName
-	fmt.Println(fmt.Sprintf("Generating relation for %s and %s with type %s", relationTableName, referenceTableName, relation.relationType))
-	if relation.relationType == OneToOne {
-		return fmt.Sprintf("ALTER TABLE \"%s\" ADD CONSTRAINT \"fk_%s\" FOREIGN KEY (\"%s\") REFERENCES \"%s\" (\"%s\") DEFERRABLE INITIALLY IMMEDIATE;", relationTableName, referenceTableName, relation.relationFieldName, referenceTableName, relation.referenceFieldName)
+func (p *PostgresController) getConstraints(tables map[string]*snapshotTable) error {
+	/*
+		SELECT cl.relname, con.conname, con.contype, con.condeferrable, con.confdeltype, con.confupdtype,
+			ARRAY(SELECT a.attname::text FROM unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord) JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum ORDER BY k.ord),
+			COALESCE(ref.relname::text, ''),
+			ARRAY(SELECT a.attname::text FROM unnest(con.confkey) WITH ORDINALITY AS k(attnum, ord) JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum ORDER BY k.ord)
+		FROM pg_constraint con
+		JOIN pg_class cl ON cl.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = cl.relnamespace
+		LEFT JOIN pg_class ref ON ref.oid = con.confrelid
+		WHERE n.nspname = 'public' AND con.contype IN ('p', 'u', 'f')
+		ORDER BY cl.relname, con.conname;
+	*/
+	const rawSqlString = "SELECT cl.relname, con.conname, con.contype, con.condeferrable, con.confdeltype, con.confupdtype, " +
+		"ARRAY(SELECT a.attname::text FROM unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord) JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum ORDER BY k.ord), " +
+		"COALESCE(ref.relname::text, ''), " +
+		"ARRAY(SELECT a.attname::text FROM unnest(con.confkey) WITH ORDINALITY AS k(attnum, ord) JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum ORDER BY k.ord) " +
+		"FROM pg_constraint con JOIN pg_class cl ON cl.oid = con.conrelid JOIN pg_namespace n ON n.oid = cl.relnamespace LEFT JOIN pg_class ref ON ref.oid = con.confrelid " +
+		"WHERE n.nspname = 'public' AND con.contype IN ('p', 'u', 'f') ORDER BY cl.relname, con.conname"
+
+	rows, err := p.db.Query(rawSqlString)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, constraintName, constraintType, deleteType, updateType, referenceTable string
+		var deferrable bool
+		var columns, referenceColumns []string
+		if err := rows.Scan(&tableName, &constraintName, &constraintType, &deferrable, &deleteType, &updateType, pq.Array(&columns), &referenceTable, pq.Array(&referenceColumns)); err != nil {
+			return err
+		}
+
+		table, exists := tables[tableName]
+		if !exists {
+			continue
+		}
+
+		switch constraintType {
+		case "p":
+			table.primaryKey = snapshotConstraint{name: constraintName, columns: columns}
+		case "u":
+			table.uniques = append(table.uniques, snapshotConstraint{name: constraintName, columns: columns})
+		case "f":
+			table.foreignKeys = append(table.foreignKeys, snapshotForeignKey{
+				name:             constraintName,
+				columns:          columns,
+				referenceTable:   referenceTable,
+				referenceColumns: referenceColumns,
+				deferrable:       deferrable,
+				onDelete:         postgresReferentialActions[deleteType],
+				onUpdate:         postgresReferentialActions[updateType],
+			})
+		}
 	}
-	return fmt.Sprintf("ALTER TABLE \"%s\" ADD CONSTRAINT \"fk_%s\" FOREIGN KEY (\"%s\") REFERENCES \"%s\" (\"%s\");", relationTableName, referenceTableName, relation.relationFieldName, referenceTableName, relation.referenceFieldName)
+
+	return rows.Err()
 }
 
-func (p *PostgresController) addTypeProperty(sqlQuery *string, property schema_model.Property, enumNames []string) error {
-	var postgresType string
-	isEnum := slices.Contains(enumNames, property.Type) || (slices.Contains(enumNames, property.Type[0:len(property.Type)-1]) && property.Type[len(property.Type)-1:len(property.Type)] == "?")
+// getIndexes reads indexes that are not created by constraints. Expression indexes can't be described by the schema, so they are skipped.
+func (p *PostgresController) getIndexes(tables map[string]*snapshotTable) error {
+	/*
+		SELECT t.relname, i.relname, ix.indisunique, am.amname, COALESCE(pg_get_expr(ix.indpred, ix.indrelid), ''),
+			ARRAY(SELECT a.attname::text FROM unnest(ix.indkey) WITH ORDINALITY AS k(attnum, ord) JOIN pg_attribute a ON a.attrelid = ix.indrelid AND a.attnum = k.attnum ORDER BY k.ord),
+			ARRAY(SELECT (k.flags & 1) = 1 FROM unnest(ix.indoption) WITH ORDINALITY AS k(flags, ord) ORDER BY k.ord)
+		FROM pg_index ix
+		JOIN pg_class t ON t.oid = ix.indrelid
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_am am ON am.oid = i.relam
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		WHERE n.nspname = 'public' AND ix.indexprs IS NULL AND NOT EXISTS (SELECT 1 FROM pg_constraint con WHERE con.conindid = ix.indexrelid)
+		ORDER BY t.relname, i.relname;
+	*/
+	const rawSqlString = "SELECT t.relname, i.relname, ix.indisunique, am.amname, COALESCE(pg_get_expr(ix.indpred, ix.indrelid), ''), " +
+		"ARRAY(SELECT a.attname::text FROM unnest(ix.indkey) WITH ORDINALITY AS k(attnum, ord) JOIN pg_attribute a ON a.attrelid = ix.indrelid AND a.attnum = k.attnum ORDER BY k.ord), " +
+		"ARRAY(SELECT (k.flags & 1) = 1 FROM unnest(ix.indoption) WITH ORDINALITY AS k(flags, ord) ORDER BY k.ord) " +
+		"FROM pg_index ix JOIN pg_class t ON t.oid = ix.indrelid JOIN pg_class i ON i.oid = ix.indexrelid JOIN pg_am am ON am.oid = i.relam JOIN pg_namespace n ON n.oid = t.relnamespace " +
+		"WHERE n.nspname = 'public' AND ix.indexprs IS NULL AND NOT EXISTS (SELECT 1 FROM pg_constraint con WHERE con.conindid = ix.indexrelid) ORDER BY t.relname, i.relname"
 
-	if !isEnum {
-		pType, isValidType := property.GetPostgresType()
-		if !isValidType {
-			return errors.New(fmt.Sprintf("Invalid property type provided: %s", property.Type))
+	rows, err := p.db.Query(rawSqlString)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, indexName, method, where string
+		var unique bool
+		var columns []string
+		var descending []bool
+		if err := rows.Scan(&tableName, &indexName, &unique, &method, &where, pq.Array(&columns), pq.Array(&descending)); err != nil {
+			return err
 		}
-		postgresType = pType
-	} else {
-		postgresType = property.Type
+
+		table, exists := tables[tableName]
+		if !exists {
+			continue
+		}
+		table.indexes = append(table.indexes, p.createSnapshotIndex(indexName, unique, method, where, columns, descending))
 	}
 
-	if property.Default == "autoincrement()" {
-		*sqlQuery += " SERIAL"
-		return nil
+	return rows.Err()
+}
+
+// createSnapshotIndex converts index read from pg_index to the index of the snapshot, descending contains sort flag of every column
+func (p *PostgresController) createSnapshotIndex(name string, unique bool, method string, where string, columns []string, descending []bool) snapshotIndex {
+	index := snapshotIndex{name: name, columns: columns, unique: unique}
+	if method != string(schema_model.BTree) {
+		index.method = method
+	}
+	// predicate is printed in parentheses
+	if strings.HasPrefix(where, "(") && strings.HasSuffix(where, ")") {
+		where = where[1 : len(where)-1]
 	}
+	index.where = where
+	for columnIndex, isDescending := range descending {
+		if isDescending && columnIndex < len(columns) {
+			index.descending = append(index.descending, columns[columnIndex])
+		}
+	}
+	return index
+}
 
-	if property.Default == "uuid()" {
-		*sqlQuery += "  uuid"
-		return nil
+// normalizeDefaultValue converts default expression returned by postgres to the form used in snapshots
+func (p *PostgresController) normalizeDefaultValue(expression string) (defaultValue string, autoincrement bool) {
+	if expression == "" {
+		return "", false
 	}
 
-	isOptional := property.Type[len(property.Type)-1:len(property.Type)] == "?"
+	if strings.HasPrefix(expression, "nextval(") {
+		return "", true
+	}
 
-	if isEnum && isOptional {
-		*sqlQuery += fmt.Sprintf(" \"%s\"", postgresType[0:len(postgresType)-1])
-	} else if isEnum && !isOptional {
-		*sqlQuery += fmt.Sprintf(" \"%s\" NOT NULL", postgresType)
-	} else {
-		*sqlQuery += fmt.Sprintf(" %s", postgresType)
+	for {
+		matches := postgresCastRegexp.FindStringSubmatch(expression)
+		if matches == nil {
+			break
+		}
+		expression = matches[1]
+	}
+
+	if strings.HasPrefix(expression, "(") && strings.HasSuffix(expression, ")") {
+		expression = expression[1 : len(expression)-1]
+	}
+
+	if strings.HasPrefix(expression, "'") && strings.HasSuffix(expression, "'") && len(expression) > 1 {
+		expression = strings.ReplaceAll(expression[1:len(expression)-1], "''", "'")
 	}
 
+	return expression, false
+}
+
+func (p *PostgresController) checkConnection() error {
+	if err := p.db.Ping(); err != nil {
+		return database_error.DatabaseError{
+			ErrorType: database_error.ConnectionError,
+			Text:      fmt.Sprintf("Can't connect to db: %s", err),
+		}
+	}
 	return nil
 }
 
-func (p *PostgresController) addIdProperty(sqlQuery *string, property schema_model.Property) {
-	if property.Id {
-		*sqlQuery += " PRIMARY KEY"
+func (p *PostgresController) generateColumnType(column snapshotColumn) string {
+	if column.enumName != "" {
+		return fmt.Sprintf("\"%s\"", column.enumName)
+	}
+	return column.dataType
+}
+
+func (p *PostgresController) generateDefaultValue(column snapshotColumn) string {
+	if strings.HasSuffix(column.defaultValue, ")") {
+		return column.defaultValue
+	}
+
+	isQuoted := column.enumName != "" || column.dataType == "text" || column.dataType == "uuid" || strings.HasPrefix(column.dataType, "timestamp") || strings.HasPrefix(column.dataType, "character")
+	if isQuoted {
+		return fmt.Sprintf("'%s'", strings.ReplaceAll(column.defaultValue, "'", "''"))
 	}
+
+	return column.defaultValue
 }
 
-func (p *PostgresController) addUniqueProperty(sqlQuery *string, property schema_model.Property) {
-	if property.Unique {
-		*sqlQuery += " UNIQUE"
+func (p *PostgresController) generateColumnDefinition(column snapshotColumn) string {
+	//"id" SERIAL NOT NULL
+	if column.autoincrement {
+		return fmt.Sprintf("\"%s\" SERIAL NOT NULL", column.name)
+	}
+
+	definition := fmt.Sprintf("\"%s\" %s", column.name, p.generateColumnType(column))
+	if !column.nullable {
+		definition += " NOT NULL"
+	}
+	if column.defaultValue != "" {
+		definition += fmt.Sprintf(" DEFAULT(%s)", p.generateDefaultValue(column))
 	}
+	return definition
 }
 
-func (p *PostgresController) addDefaultProperty(sqlQuery *string, property schema_model.Property) error {
-	if property.Default == "uuid()" {
-		*sqlQuery += "  DEFAULT(gen_random_uuid())"
-		return nil
+func (p *PostgresController) generateColumnList(columns []string) string {
+	quotedColumns := make([]string, len(columns))
+	for index, column := range columns {
+		quotedColumns[index] = fmt.Sprintf("\"%s\"", column)
 	}
-	if property.Default == "now()" {
-		*sqlQuery += "  DEFAULT(now())"
-		return nil
+	return strings.Join(quotedColumns, ", ")
+}
+
+func (p *PostgresController) generateCreateTableSqlScriptFromTable(table snapshotTable) string {
+	//CREATE TABLE "User" ("id" SERIAL NOT NULL, CONSTRAINT "User_pkey" PRIMARY KEY ("id"));
+	var definitions []string
+	for _, column := range table.columns {
+		definitions = append(definitions, p.generateColumnDefinition(column))
 	}
-	if property.Default != "" && property.Default != "autoincrement()" {
-		//fmt.Println(fmt.Sprintf("Property %s has default value %s of type %T", property.Name, property.Default, property.Default))
-		_, err := property.ValidateDefaultValue()
-		if err != nil {
-			return err
+	if len(table.primaryKey.columns) != 0 {
+		definitions = append(definitions, fmt.Sprintf("CONSTRAINT \"%s\" PRIMARY KEY (%s)", table.primaryKey.name, p.generateColumnList(table.primaryKey.columns)))
+	}
+	for _, unique := range table.uniques {
+		definitions = append(definitions, fmt.Sprintf("CONSTRAINT \"%s\" UNIQUE (%s)", unique.name, p.generateColumnList(unique.columns)))
+	}
+	return fmt.Sprintf("CREATE TABLE \"%s\" (\n\t%s\n);", table.name, strings.Join(definitions, ",\n\t"))
+}
+
+func (p *PostgresController) generateDeleteTableSqlScriptFromDbTableName(tableName string) string {
+	//DROP TABLE User CASCADE;
+	return fmt.Sprintf("DROP TABLE \"%s\" CASCADE;", tableName)
+}
+
+func (p *PostgresController) generateAddColumnSqlScript(tableName string, column snapshotColumn) string {
+	//ALTER TABLE "User" ADD COLUMN "email" text NOT NULL;
+	return fmt.Sprintf("ALTER TABLE \"%s\" ADD COLUMN %s;", tableName, p.generateColumnDefinition(column))
+}
+
+func (p *PostgresController) generateDropColumnSqlScript(tableName string, columnName string) string {
+	//ALTER TABLE "User" DROP COLUMN "email";
+	return fmt.Sprintf("ALTER TABLE \"%s\" DROP COLUMN \"%s\";", tableName, columnName)
+}
+
+func (p *PostgresController) generateSetDefaultSqlScripts(tableName string, current snapshotColumn, target snapshotColumn) []MigrationStep {
+	if target.autoincrement {
+		//CREATE SEQUENCE IF NOT EXISTS "User_id_seq" OWNED BY "User"."id";
+		sequenceName := fmt.Sprintf("%s_%s_seq", tableName, target.name)
+		steps := []MigrationStep{
+			{Query: fmt.Sprintf("CREATE SEQUENCE IF NOT EXISTS \"%s\" OWNED BY \"%s\".\"%s\";", sequenceName, tableName, target.name)},
+			{Query: fmt.Sprintf("ALTER TABLE \"%s\" ALTER COLUMN \"%s\" SET DEFAULT nextval('\"%s\"');", tableName, target.name, sequenceName)},
+		}
+		if !current.autoincrement {
+			steps = append(steps, MigrationStep{Query: fmt.Sprintf("SELECT setval('\"%s\"', COALESCE(MAX(\"%s\"), 0) + 1, false) FROM \"%s\";", sequenceName, target.name, tableName)})
 		}
-		*sqlQuery += fmt.Sprintf(" DEFAULT(%s)", property.Default)
+		return steps
 	}
+
+	if target.defaultValue != "" {
+		return []MigrationStep{{Query: fmt.Sprintf("ALTER TABLE \"%s\" ALTER COLUMN \"%s\" SET DEFAULT(%s);", tableName, target.name, p.generateDefaultValue(target))}}
+	}
+
 	return nil
 }
 
-func (p *PostgresController) generateCreateTableWithoutRelationsSqlScriptFromModel(model schema_model.Model, models []schema_model.Model, enumNames []string, tableNames []string, tableQueries *[]string, relationQueries *[]string) error {
-	rawSqlQuery := fmt.Sprintf("CREATE TABLE \"%s\" (", model.Name)
-	for propertyIndex, property := range model.Properties {
-		if property.RelationField != "" && property.ReferenceField != "" {
-			relation, err := p.defineRelation(model, models, propertyIndex)
-			if err != nil {
-				return err
-			}
+func (p *PostgresController) generateAlterColumnSqlScripts(change columnChange) []MigrationStep {
+	var steps []MigrationStep
+	current, target := change.current, change.target
 
-			*relationQueries = append(*relationQueries, p.generateRelationsSqlScriptFromProperty(relation))
-		}
+	hasCurrentDefault := current.defaultValue != "" || current.autoincrement
+	isTypeChanged := current.dataType != target.dataType || current.enumName != target.enumName
+	isDefaultChanged := current.defaultValue != target.defaultValue || current.autoincrement != target.autoincrement
 
-		propertyType := property.Type
-		if strings.Contains(propertyType, "[]") {
-			propertyType = propertyType[0 : len(propertyType)-2]
+	if isTypeChanged {
+		if hasCurrentDefault {
+			steps = append(steps, MigrationStep{Query: fmt.Sprintf("ALTER TABLE \"%s\" ALTER COLUMN \"%s\" DROP DEFAULT;", change.table, target.name)})
 		}
-		if strings.Contains(propertyType, "?") {
-			propertyType = propertyType[0 : len(propertyType)-1]
+
+		columnType := p.generateColumnType(target)
+		using := fmt.Sprintf("\"%s\"::%s", target.name, columnType)
+		if target.enumName != "" || current.enumName != "" {
+			using = fmt.Sprintf("\"%s\"::text::%s", target.name, columnType)
 		}
-		if slices.Contains(tableNames, propertyType) {
-			continue
+
+		steps = append(steps, MigrationStep{
+			Query:   fmt.Sprintf("ALTER TABLE \"%s\" ALTER COLUMN \"%s\" TYPE %s USING (%s);", change.table, target.name, columnType, using),
+			Warning: fmt.Sprintf("Type of column \"%s\" of table \"%s\" will be changed from %s to %s. Migration will fail if existing values can't be cast", target.name, change.table, current.dataType, target.dataType),
+		})
+	}
+
+	if isTypeChanged || isDefaultChanged {
+		setDefaultSteps := p.generateSetDefaultSqlScripts(change.table, current, target)
+		steps = append(steps, setDefaultSteps...)
+		if len(setDefaultSteps) == 0 && !isTypeChanged && hasCurrentDefault {
+			steps = append(steps, MigrationStep{Query: fmt.Sprintf("ALTER TABLE \"%s\" ALTER COLUMN \"%s\" DROP DEFAULT;", change.table, target.name)})
 		}
+	}
 
-		if property.RelationField == "" && property.ReferenceField == "" {
-			var propertyString string
+	if current.nullable && !target.nullable {
+		steps = append(steps, MigrationStep{
+			Query:   fmt.Sprintf("ALTER TABLE \"%s\" ALTER COLUMN \"%s\" SET NOT NULL;", change.table, target.name),
+			Warning: fmt.Sprintf("Column \"%s\" of table \"%s\" becomes required. Migration will fail if it contains NULL values", target.name, change.table),
+		})
+	}
 
-			propertyString = fmt.Sprintf("\"%s\"", property.Name)
+	if !current.nullable && target.nullable {
+		steps = append(steps, MigrationStep{Query: fmt.Sprintf("ALTER TABLE \"%s\" ALTER COLUMN \"%s\" DROP NOT NULL;", change.table, target.name)})
+	}
 
-			if err := p.addTypeProperty(&propertyString, property, enumNames); err != nil {
-				return err
-			}
-			p.addIdProperty(&propertyString, property)
-			p.addUniqueProperty(&propertyString, property)
-			if err := p.addDefaultProperty(&propertyString, property); err != nil {
-				return err
-			}
+	return steps
+}
 
-			propertyString += ","
+func (p *PostgresController) generateDropConstraintSqlScript(tableName string, constraintName string) string {
+	//ALTER TABLE "Todo" DROP CONSTRAINT "fk_User";
+	return fmt.Sprintf("ALTER TABLE \"%s\" DROP CONSTRAINT \"%s\";", tableName, constraintName)
+}
 
-			rawSqlQuery = rawSqlQuery + propertyString
-			continue
+func (p *PostgresController) generateAddPrimaryKeySqlScript(tableName string, primaryKey snapshotConstraint) string {
+	//ALTER TABLE "User" ADD CONSTRAINT "User_pkey" PRIMARY KEY ("id");
+	return fmt.Sprintf("ALTER TABLE \"%s\" ADD CONSTRAINT \"%s\" PRIMARY KEY (%s);", tableName, primaryKey.name, p.generateColumnList(primaryKey.columns))
+}
+
+func (p *PostgresController) generateAddUniqueSqlScript(tableName string, unique snapshotConstraint) string {
+	//ALTER TABLE "User" ADD CONSTRAINT "User_email_key" UNIQUE ("email");
+	return fmt.Sprintf("ALTER TABLE \"%s\" ADD CONSTRAINT \"%s\" UNIQUE (%s);", tableName, unique.name, p.generateColumnList(unique.columns))
+}
+
+func (p *PostgresController) generateCreateIndexSqlScript(tableName string, index snapshotIndex) string {
+	//CREATE UNIQUE INDEX "Post_authorId_createdAt_key" ON "Post" USING btree ("authorId", "createdAt" DESC) WHERE "deletedAt" IS NULL;
+	rawSqlString := "CREATE INDEX"
+	if index.unique {
+		rawSqlString = "CREATE UNIQUE INDEX"
+	}
+	rawSqlString += fmt.Sprintf(" \"%s\" ON \"%s\"", index.name, tableName)
+	if index.method != "" {
+		rawSqlString += fmt.Sprintf(" USING %s", index.method)
+	}
+
+	columns := make([]string, len(index.columns))
+	for columnIndex, column := range index.columns {
+		columns[columnIndex] = fmt.Sprintf("\"%s\"", column)
+		if slices.Contains(index.descending, column) {
+			columns[columnIndex] += " DESC"
 		}
 	}
+	rawSqlString += fmt.Sprintf(" (%s)", strings.Join(columns, ", "))
 
-	rawSqlQuery = rawSqlQuery[:len(rawSqlQuery)-1] + ");"
-	*tableQueries = append(*tableQueries, rawSqlQuery)
-	return nil
+	if index.where != "" {
+		rawSqlString += fmt.Sprintf(" WHERE %s", index.where)
+	}
+	return rawSqlString + ";"
+}
+
+func (p *PostgresController) generateDropIndexSqlScript(indexName string) string {
+	//DROP INDEX "Post_authorId_createdAt_idx";
+	return fmt.Sprintf("DROP INDEX \"%s\";", indexName)
+}
+
+func (p *PostgresController) generateRelationsSqlScriptFromForeignKey(tableName string, foreignKey snapshotForeignKey) string {
+	//ALTER TABLE "Todo" ADD CONSTRAINT "fk_User" FOREIGN KEY ("userId") REFERENCES "User" ("id") ON DELETE CASCADE;
+	rawSqlString := fmt.Sprintf("ALTER TABLE \"%s\" ADD CONSTRAINT \"%s\" FOREIGN KEY (%s) REFERENCES \"%s\" (%s)", tableName, foreignKey.name, p.generateColumnList(foreignKey.columns), foreignKey.referenceTable, p.generateColumnList(foreignKey.referenceColumns))
+	rawSqlString += generateReferentialActions(foreignKey)
+	if foreignKey.deferrable {
+		rawSqlString += " DEFERRABLE INITIALLY IMMEDIATE"
+	}
+	return rawSqlString + ";"
 }
 
-func (p *PostgresController) generateDeleteEnumSqlScriptFromDbEnum(enum databaseEnum) string {
+func (p *PostgresController) generateDeleteEnumSqlScriptFromEnum(enum snapshotEnum) string {
 	//DROP TYPE UserRole;
-	return fmt.Sprintf("DROP TYPE \"%s\";", enum.TypeName)
+	return fmt.Sprintf("DROP TYPE \"%s\";", enum.name)
 }
 
-func (p *PostgresController) generateCreateEnumSqlScriptFromEnum(enum schema_model.Enum) string {
+func (p *PostgresController) generateCreateEnumSqlScriptFromEnum(enum snapshotEnum) string {
 	//CREATE TYPE UserRole AS ENUM('Admin','User');
-	rawSqlString := fmt.Sprintf("CREATE TYPE \"%s\" AS ENUM (", enum.Name)
-	for index, value := range enum.Values {
-		if index == len(enum.Values)-1 {
+	rawSqlString := fmt.Sprintf("CREATE TYPE \"%s\" AS ENUM (", enum.name)
+	for index, value := range enum.values {
+		if index == len(enum.values)-1 {
 			rawSqlString += fmt.Sprintf("'%s');", value)
 			continue
 		}
@@ -482,3 +995,65 @@ func (p *PostgresController) generateCreateEnumSqlScriptFromEnum(enum schema_mod
 	}
 	return rawSqlString
 }
+
+func (p *PostgresController) generateAlterEnumSqlScripts(change enumChange, diff snapshotDiff) []MigrationStep {
+	if isSubsequence(change.current.values, change.target.values) {
+		//ALTER TYPE "UserRole" ADD VALUE IF NOT EXISTS 'Moderator' AFTER 'Admin';
+		var steps []MigrationStep
+		for index, value := range change.target.values {
+			if slices.Contains(change.current.values, value) {
+				continue
+			}
+			if index > 0 {
+				steps = append(steps, MigrationStep{Query: fmt.Sprintf("ALTER TYPE \"%s\" ADD VALUE IF NOT EXISTS '%s' AFTER '%s';", change.target.name, value, change.target.values[index-1])})
+				continue
+			}
+			steps = append(steps, MigrationStep{Query: fmt.Sprintf("ALTER TYPE \"%s\" ADD VALUE IF NOT EXISTS '%s' BEFORE '%s';", change.target.name, value, change.current.values[0])})
+		}
+		return steps
+	}
+
+	// Postgres can't remove or reorder enum values, so enum is recreated and all columns are converted to the new type
+	var removedValues []string
+	for _, value := range change.current.values {
+		if !slices.Contains(change.target.values, value) {
+			removedValues = append(removedValues, value)
+		}
+	}
+
+	oldEnumName := fmt.Sprintf("%s_old", change.current.name)
+	steps := []MigrationStep{
+		{Query: fmt.Sprintf("ALTER TYPE \"%s\" RENAME TO \"%s\";", change.current.name, oldEnumName)},
+		{Query: p.generateCreateEnumSqlScriptFromEnum(change.target)},
+	}
+	if len(removedValues) != 0 {
+		steps[0].Warning = fmt.Sprintf("Values %s will be removed from enum \"%s\". Migration will fail if they are still used", strings.Join(removedValues, ", "), change.current.name)
+	}
+
+	for _, currentTable := range diff.current.tables {
+		if _, exists := diff.target.findTable(currentTable.name); !exists {
+			continue
+		}
+		for _, column := range currentTable.columns {
+			if column.enumName != change.current.name {
+				continue
+			}
+			isDropped := slices.ContainsFunc(diff.droppedColumns, func(c tableColumn) bool {
+				return c.table == currentTable.name && c.column.name == column.name
+			})
+			if isDropped {
+				continue
+			}
+			if column.defaultValue != "" {
+				steps = append(steps, MigrationStep{Query: fmt.Sprintf("ALTER TABLE \"%s\" ALTER COLUMN \"%s\" DROP DEFAULT;", currentTable.name, column.name)})
+			}
+			steps = append(steps, MigrationStep{Query: fmt.Sprintf("ALTER TABLE \"%s\" ALTER COLUMN \"%s\" TYPE \"%s\" USING (\"%s\"::text::\"%s\");", currentTable.name, column.name, change.target.name, column.name, change.target.name)})
+			if column.defaultValue != "" {
+				steps = append(steps, MigrationStep{Query: fmt.Sprintf("ALTER TABLE \"%s\" ALTER COLUMN \"%s\" SET DEFAULT(%s);", currentTable.name, column.name, p.generateDefaultValue(column))})
+			}
+		}
+	}
+
+	steps = append(steps, MigrationStep{Query: fmt.Sprintf("DROP TYPE \"%s\";", oldEnumName)})
+	return steps
+}
@@ -0,0 +1,132 @@
+package database_contoller
+
+import (
+	"GoRelCli/models/schema_model"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const postgresEnumSchema = `
+models:
+  - name: User
+    properties:
+      - name: id
+        type: int
+        default: autoincrement()
+        id: true
+      - name: role
+        type: UserRole
+enums:
+  - name: UserRole
+    values: [user, moderator, admin]
+`
+
+func TestPostgresAlterEnum(t *testing.T) {
+	tests := []struct {
+		name   string
+		values string
+	}{
+		{name: "enum_add_value", values: "[guest, user, moderator, editor, admin]"},
+		{name: "enum_remove_value", values: "[user, admin]"},
+		{name: "enum_reorder_values", values: "[admin, moderator, user]"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			controller, current := testSnapshot(t, schema_model.PostgreSQL, postgresEnumSchema)
+			_, target := testSnapshot(t, schema_model.PostgreSQL, strings.Replace(postgresEnumSchema, "[user, moderator, admin]", test.values, 1))
+			steps := controller.generateMigrationSteps(diffSnapshots(current, target))
+			assertGolden(t, filepath.Join("testdata", "postgres", test.name+"_migration.sql"), GenerateSqlScript(steps))
+		})
+	}
+}
+
+func TestSplitAddEnumValueStatements(t *testing.T) {
+	script := `-- Warning: Type of column "age" of table "User" will be changed from text to integer. Migration will fail if existing values can't be converted
+ALTER TABLE "User" ALTER COLUMN "age" TYPE integer USING ("age"::integer);
+ALTER TYPE "UserRole" ADD VALUE IF NOT EXISTS 'guest' BEFORE 'user';
+ALTER TYPE "UserRole" ADD VALUE 'moderator' AFTER 'user';
+ALTER TYPE "UserRole" RENAME TO "UserRole_old";
+`
+	enumStatements, rest := splitAddEnumValueStatements(script)
+
+	wantStatements := []string{
+		`ALTER TYPE "UserRole" ADD VALUE IF NOT EXISTS 'guest' BEFORE 'user';`,
+		`ALTER TYPE "UserRole" ADD VALUE 'moderator' AFTER 'user';`,
+	}
+	if !slices.Equal(enumStatements, wantStatements) {
+		t.Errorf("enum statements = %q, want %q", enumStatements, wantStatements)
+	}
+	wantRest := `-- Warning: Type of column "age" of table "User" will be changed from text to integer. Migration will fail if existing values can't be converted
+ALTER TABLE "User" ALTER COLUMN "age" TYPE integer USING ("age"::integer);
+ALTER TYPE "UserRole" RENAME TO "UserRole_old";
+`
+	if rest != wantRest {
+		t.Errorf("rest of script = %q, want %q", rest, wantRest)
+	}
+}
+
+func TestPostgresIndexRoundTrip(t *testing.T) {
+	controller, target := testSnapshot(t, schema_model.PostgreSQL, `
+models:
+  - name: Post
+    properties:
+      - name: id
+        type: int
+        default: autoincrement()
+        id: true
+      - name: status
+        type: string
+      - name: role
+        type: UserRole
+      - name: title
+        type: string
+      - name: tags
+        type: string[]
+      - name: deletedAt
+        type: dateTime?
+    indexes:
+      - fields: [{name: status}, {name: title}]
+        unique: true
+      - fields: [{name: title}]
+        where: '"deletedAt" IS NULL'
+      - name: Post_status_active_key
+        fields: [{name: status}]
+        unique: true
+        where: status = 'active'
+      - name: Post_role_admin_idx
+        fields: [{name: title}]
+        where: role = 'admin'
+      - name: Post_title_hash_idx
+        fields: [{name: title}]
+        using: hash
+      - name: Post_tags_idx
+        fields: [{name: tags}]
+        using: gin
+      - name: Post_recent_idx
+        fields: [{name: deletedAt, sort: desc}, {name: id}]
+      - name: Post_title_desc_key
+        fields: [{name: title, sort: desc}]
+        unique: true
+enums:
+  - name: UserRole
+    values: [user, admin]
+`)
+	post, _ := target.findTable("Post")
+
+	// values are written the way pg_index and pg_get_expr return them
+	postgres := controller.(*PostgresController)
+	introspected := replaceIndexes(target, "Post", post.uniques, []snapshotIndex{
+		postgres.createSnapshotIndex("Post_recent_idx", false, "btree", "", []string{"deletedAt", "id"}, []bool{true, false}),
+		postgres.createSnapshotIndex("Post_role_admin_idx", false, "btree", `(role = 'admin'::"UserRole")`, []string{"title"}, []bool{false}),
+		postgres.createSnapshotIndex("Post_status_active_key", true, "btree", "((status)::text = 'active'::text)", []string{"status"}, []bool{false}),
+		postgres.createSnapshotIndex("Post_tags_idx", false, "gin", "", []string{"tags"}, []bool{false}),
+		postgres.createSnapshotIndex("Post_title_desc_key", true, "btree", "", []string{"title"}, []bool{true}),
+		postgres.createSnapshotIndex("Post_title_hash_idx", false, "hash", "", []string{"title"}, []bool{false}),
+		postgres.createSnapshotIndex("Post_title_idx", false, "btree", `("deletedAt" IS NULL)`, []string{"title"}, []bool{false}),
+	})
+
+	assertRoundTrip(t, controller, schema_model.PostgreSQL, target, introspected)
+}
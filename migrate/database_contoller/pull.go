@@ -0,0 +1,272 @@
+package database_contoller
+
+import (
+	"GoRelCli/models/schema_model"
+	"GoRelCli/utils/naming"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// PullSchema reads structure of the database and converts it to models and enums of the schema.
+// Warnings describe parts of the database that can't be described by the schema exactly.
+func PullSchema(controller DatabaseControllerInterface) (models []schema_model.Model, enums []schema_model.Enum, warnings []string, err error) {
+	snapshot, err := controller.getSnapshot()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	models, enums, warnings = createSchemaFromSnapshot(controller, snapshot)
+	return models, enums, warnings, nil
+}
+
+// createSchemaFromSnapshot converts tables of the snapshot to models and enums of the schema
+func createSchemaFromSnapshot(controller DatabaseControllerInterface, snapshot databaseSnapshot) (models []schema_model.Model, enums []schema_model.Enum, warnings []string) {
+	for _, table := range snapshot.tables {
+		// join tables of many-to-many relations become list properties of both models
+		if isJoinTable(table) {
+			continue
+		}
+		model, modelWarnings := createModelFromTable(controller, table, &snapshot)
+		models = append(models, model)
+		warnings = append(warnings, modelWarnings...)
+	}
+
+	for _, enum := range snapshot.enums {
+		enums = append(enums, schema_model.Enum{Name: enum.name, Values: enum.values})
+	}
+
+	warnings = append(warnings, addRelations(models, snapshot)...)
+
+	return models, enums, warnings
+}
+
+// createModelFromTable converts table to model. Inline enums of columns are added to the snapshot, columns with the same values share one enum.
+func createModelFromTable(controller DatabaseControllerInterface, table snapshotTable, snapshot *databaseSnapshot) (schema_model.Model, []string) {
+	model := schema_model.Model{Name: table.name}
+	var warnings []string
+
+	for _, column := range table.columns {
+		// primary key columns can't contain nulls even if they are not declared as NOT NULL (e.g. INTEGER PRIMARY KEY in sqlite)
+		if slices.Contains(table.primaryKey.columns, column.name) {
+			column.nullable = false
+		}
+
+		property, inlineEnum, propertyWarnings := controller.createSchemaProperty(table.name, column)
+		warnings = append(warnings, propertyWarnings...)
+
+		if inlineEnum != nil {
+			index := slices.IndexFunc(snapshot.enums, func(enum snapshotEnum) bool { return slices.Equal(enum.values, inlineEnum.values) })
+			if index == -1 {
+				snapshot.enums = append(snapshot.enums, *inlineEnum)
+			} else {
+				property.Type = strings.Replace(property.Type, inlineEnum.name, snapshot.enums[index].name, 1)
+			}
+		}
+
+		if len(table.primaryKey.columns) == 1 && table.primaryKey.columns[0] == column.name {
+			property.Id = true
+		}
+		property.Unique = slices.ContainsFunc(table.uniques, func(unique snapshotConstraint) bool {
+			return len(unique.columns) == 1 && unique.columns[0] == column.name
+		})
+
+		model.Properties = append(model.Properties, property)
+	}
+
+	switch {
+	case len(table.primaryKey.columns) == 0:
+		warnings = append(warnings, fmt.Sprintf("Table \"%s\" has no primary key. Add id property manually", table.name))
+	case len(table.primaryKey.columns) > 1:
+		model.PrimaryKey = table.primaryKey.columns
+	}
+
+	for _, unique := range table.uniques {
+		if len(unique.columns) > 1 {
+			model.Indexes = append(model.Indexes, createIndexFromSnapshot(table.name, snapshotIndex{name: unique.name, columns: unique.columns, unique: true}))
+		}
+	}
+	for _, index := range table.indexes {
+		model.Indexes = append(model.Indexes, createIndexFromSnapshot(table.name, index))
+	}
+
+	return model, warnings
+}
+
+// createIndexFromSnapshot converts index of the table to index of the model, default names are omitted
+func createIndexFromSnapshot(tableName string, index snapshotIndex) schema_model.Index {
+	result := schema_model.Index{Unique: index.unique, Using: schema_model.IndexMethod(index.method), Where: index.where}
+	for _, column := range index.columns {
+		field := schema_model.IndexField{Name: column}
+		if slices.Contains(index.descending, column) {
+			field.Sort = schema_model.Desc
+		}
+		result.Fields = append(result.Fields, field)
+	}
+	if result.GetName(tableName) != index.name {
+		result.Name = index.name
+	}
+	return result
+}
+
+// addRelations adds both sides of every foreign key to the models: property with relationField and referenceField to the model
+// that has foreign key and back reference to the referenced model. Back reference is a list unless foreign key is deferrable (one to one).
+func addRelations(models []schema_model.Model, snapshot databaseSnapshot) []string {
+	var warnings []string
+
+	findModel := func(name string) *schema_model.Model {
+		for index := range models {
+			if models[index].Name == name {
+				return &models[index]
+			}
+		}
+		return nil
+	}
+
+	for _, table := range snapshot.tables {
+		if isJoinTable(table) {
+			warnings = append(warnings, addJoinRelation(table, findModel)...)
+			continue
+		}
+		for _, foreignKey := range table.foreignKeys {
+			relationModel, referenceModel := findModel(table.name), findModel(foreignKey.referenceTable)
+			if len(foreignKey.columns) != 1 || relationModel == nil || referenceModel == nil {
+				warnings = append(warnings, fmt.Sprintf("Foreign key \"%s\" of table \"%s\" is skipped, because only single column foreign keys are supported", foreignKey.name, table.name))
+				continue
+			}
+
+			relationName := getRelationName(table, foreignKey)
+			backReferenceName := naming.LowerFirst(table.name)
+			if relationName != "" {
+				backReferenceName = relationName + table.name
+			}
+
+			// Back reference is added first, so it is found by defineRelation when table references itself
+			backReference := schema_model.Property{Type: fmt.Sprintf("%s[]", table.name), RelationName: relationName}
+			backReference.Name = getUniquePropertyName(*referenceModel, naming.Pluralize(backReferenceName))
+			if foreignKey.deferrable {
+				backReference.Type = table.name
+				backReference.Name = getUniquePropertyName(*referenceModel, backReferenceName)
+			}
+			referenceModel.Properties = append(referenceModel.Properties, backReference)
+
+			relationModel.Properties = append(relationModel.Properties, schema_model.Property{
+				Name:           getUniquePropertyName(*relationModel, getRelationPropertyName(foreignKey)),
+				Type:           foreignKey.referenceTable,
+				RelationName:   relationName,
+				RelationField:  foreignKey.columns[0],
+				ReferenceField: foreignKey.referenceColumns[0],
+				OnDelete:       schema_model.ParseReferentialAction(foreignKey.onDelete),
+				OnUpdate:       schema_model.ParseReferentialAction(foreignKey.onUpdate),
+			})
+		}
+	}
+
+	return warnings
+}
+
+// isJoinTable checks if table is created for implicit many-to-many relation: it has only columns A and B, which are primary key and reference other tables
+func isJoinTable(table snapshotTable) bool {
+	if !strings.HasPrefix(table.name, "_") || len(table.columns) != 2 || len(table.foreignKeys) != 2 {
+		return false
+	}
+	columns := []string{table.columns[0].name, table.columns[1].name}
+	slices.Sort(columns)
+	primaryKey := slices.Clone(table.primaryKey.columns)
+	slices.Sort(primaryKey)
+	if !slices.Equal(columns, []string{"A", "B"}) || !slices.Equal(primaryKey, columns) {
+		return false
+	}
+	return slices.ContainsFunc(table.foreignKeys, func(foreignKey snapshotForeignKey) bool { return slices.Equal(foreignKey.columns, []string{"A"}) }) &&
+		slices.ContainsFunc(table.foreignKeys, func(foreignKey snapshotForeignKey) bool { return slices.Equal(foreignKey.columns, []string{"B"}) })
+}
+
+// addJoinRelation adds list properties of many-to-many relation to both models referenced by the join table
+func addJoinRelation(table snapshotTable, findModel func(name string) *schema_model.Model) []string {
+	first := table.foreignKeys[slices.IndexFunc(table.foreignKeys, func(foreignKey snapshotForeignKey) bool { return foreignKey.columns[0] == "A" })]
+	second := table.foreignKeys[slices.IndexFunc(table.foreignKeys, func(foreignKey snapshotForeignKey) bool { return foreignKey.columns[0] == "B" })]
+	firstModel, secondModel := findModel(first.referenceTable), findModel(second.referenceTable)
+	if firstModel == nil || secondModel == nil || first.referenceTable > second.referenceTable {
+		return []string{fmt.Sprintf("Join table \"%s\" is skipped, because column A should reference table that goes first in alphabetical order", table.name)}
+	}
+
+	relationName := ""
+	if table.name != fmt.Sprintf("_%sTo%s", firstModel.Name, secondModel.Name) {
+		relationName = strings.TrimPrefix(table.name, "_")
+	}
+
+	// property of column A is added first, so it gets the name that goes first in alphabetical order for self relations
+	firstModel.Properties = append(firstModel.Properties, schema_model.Property{
+		Name:         getUniquePropertyName(*firstModel, naming.Pluralize(naming.LowerFirst(secondModel.Name))),
+		Type:         fmt.Sprintf("%s[]", secondModel.Name),
+		RelationName: relationName,
+	})
+	secondModel.Properties = append(secondModel.Properties, schema_model.Property{
+		Name:         getUniquePropertyName(*secondModel, naming.Pluralize(naming.LowerFirst(firstModel.Name))),
+		Type:         fmt.Sprintf("%s[]", firstModel.Name),
+		RelationName: relationName,
+	})
+	return nil
+}
+
+// getRelationName returns name of the relation, it is set only when table has several foreign keys to the same table.
+// Names of constraints created by named relations (fk_User_sender) are kept, other relations are named after the column (senderId -> sender).
+func getRelationName(table snapshotTable, foreignKey snapshotForeignKey) string {
+	count := 0
+	for _, other := range table.foreignKeys {
+		if other.referenceTable == foreignKey.referenceTable && len(other.columns) == 1 {
+			count++
+		}
+	}
+	if count < 2 {
+		return ""
+	}
+	if name, isNamed := strings.CutPrefix(foreignKey.name, fmt.Sprintf("fk_%s_", foreignKey.referenceTable)); isNamed && name != "" {
+		return name
+	}
+	if name := getRelationPropertyName(foreignKey); name != naming.LowerFirst(foreignKey.referenceTable) {
+		return name
+	}
+	return foreignKey.columns[0]
+}
+
+// getRelationPropertyName returns name of the relation property from the name of the column (userId -> user)
+func getRelationPropertyName(foreignKey snapshotForeignKey) string {
+	column := foreignKey.columns[0]
+	for _, suffix := range []string{"Id", "_id"} {
+		if strings.HasSuffix(column, suffix) && len(column) > len(suffix) {
+			return strings.TrimSuffix(column, suffix)
+		}
+	}
+	return naming.LowerFirst(foreignKey.referenceTable)
+}
+
+func getUniquePropertyName(model schema_model.Model, name string) string {
+	isUsed := func(name string) bool {
+		return slices.ContainsFunc(model.Properties, func(property schema_model.Property) bool { return property.Name == name })
+	}
+
+	if !isUsed(name) {
+		return name
+	}
+	uniqueName := fmt.Sprintf("%sRelation", name)
+	for index := 2; isUsed(uniqueName); index++ {
+		uniqueName = fmt.Sprintf("%sRelation%d", name, index)
+	}
+	return uniqueName
+}
+
+// setPropertyDefault sets default value of the property if it is valid for the type of the property
+func setPropertyDefault(property *schema_model.Property, tableName string, defaultValue string) []string {
+	if defaultValue == "" {
+		return nil
+	}
+
+	property.Default = defaultValue
+	if _, err := property.ValidateDefaultValue(); err != nil {
+		property.Default = ""
+		return []string{fmt.Sprintf("Default value %s of column \"%s\" of table \"%s\" is not supported by the schema and is skipped", defaultValue, property.Name, tableName)}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package database_contoller
+
+import (
+	"GoRelCli/models/error_model/database_error"
+	"GoRelCli/models/schema_model"
+	"strings"
+)
+
+type relationType string
+
+const (
+	OneToOne   relationType = "OneToOne"
+	OneToMany               = "OneToMany"
+	ManyToMany              = "ManyToMany"
+)
+
+type Relation struct {
+	relationType       relationType
+	referenceModelName string
+	referenceFieldName string
+	relationModelName  string
+	relationFieldName  string
+}
+
+func defineRelation(relationModel schema_model.Model, models []schema_model.Model, propertyIndex int) (Relation, error) {
+	relation := Relation{
+		relationModelName: relationModel.Name,
+	}
+
+	relationType := relationModel.Properties[propertyIndex].Type
+	relationFieldName := relationModel.Properties[propertyIndex].RelationField
+	referenceFieldName := relationModel.Properties[propertyIndex].ReferenceField
+
+	relation.relationFieldName = relationFieldName
+	relation.referenceFieldName = referenceFieldName
+
+	referenceModelName := relationType
+	if strings.Contains(referenceModelName, "[]") {
+		referenceModelName = referenceModelName[0 : len(referenceModelName)-2]
+	}
+	if strings.Contains(referenceModelName, "?") {
+		referenceModelName = referenceModelName[0 : len(referenceModelName)-1]
+	}
+
+	referenceType := ""
+
+	for _, model := range models {
+		if model.Name == referenceModelName {
+			relation.referenceModelName = model.Name
+			if pairs := relationModel.FindRelationPairs(relationModel.Properties[propertyIndex], model); len(pairs) != 0 {
+				referenceType = pairs[0].Type
+			}
+			break
+		}
+	}
+
+	if referenceType == "" {
+		return Relation{}, database_error.DatabaseError{
+			ErrorType: database_error.SqlGenerationError,
+			Text:      "ReferenceField not found",
+		}
+	}
+
+	isReferenceTypeArray := strings.Contains(referenceType, "[]")
+	isRelationTypeArray := strings.Contains(relationType, "[]")
+
+	if isReferenceTypeArray && isRelationTypeArray {
+		relation.relationType = ManyToMany
+		return relation, nil
+	}
+
+	if !isReferenceTypeArray && !isRelationTypeArray {
+		relation.relationType = OneToOne
+		return relation, nil
+	}
+
+	if isReferenceTypeArray && !isRelationTypeArray {
+		relation.relationType = OneToMany
+		return relation, nil
+	}
+
+	return Relation{}, database_error.DatabaseError{
+		ErrorType: database_error.SqlGenerationError,
+		Text:      "RelationField is array type, but ReferenceField is not",
+	}
+}
@@ -0,0 +1,1027 @@
+package database_contoller
+
+import (
+	"GoRelCli/models/error_model/database_error"
+	"GoRelCli/models/schema_model"
+	"database/sql"
+	"errors"
+	"fmt"
+	_ "github.com/mattn/go-sqlite3"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sqliteUuidExpression generates random uuid v4, because sqlite has no built-in function for it
+const sqliteUuidExpression = "lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))"
+
+const sqliteCurrentTimestamp = "CURRENT_TIMESTAMP"
+
+// sqliteRebuildTablePrefix is added to the name of the temporary table used while table is rebuilt
+const sqliteRebuildTablePrefix = "_gorel_new_"
+
+var (
+	// sqliteEnumCheckRegexp matches CHECK constraint used to emulate enum (e.g. CONSTRAINT "UserRole" CHECK ("role" IN ('Admin', 'User')))
+	sqliteEnumCheckRegexp = regexp.MustCompile(`CONSTRAINT "([^"]+)" CHECK \("([^"]+)" IN \(((?:'(?:[^']|'')*'(?:, )?)*)\)\)`)
+	sqliteEnumValueRegexp = regexp.MustCompile(`'((?:[^']|'')*)'`)
+	// sqliteForeignKeyRegexp matches foreign key constraint (e.g. CONSTRAINT "fk_User" FOREIGN KEY ("userId") REFERENCES "User" ("id") ON DELETE CASCADE)
+	sqliteForeignKeyRegexp = regexp.MustCompile(`CONSTRAINT "([^"]+)" FOREIGN KEY \(([^)]*)\) REFERENCES "([^"]+)" \(([^)]*)\)((?: ON (?:DELETE|UPDATE) (?:CASCADE|RESTRICT|SET NULL|SET DEFAULT|NO ACTION))*)( DEFERRABLE INITIALLY IMMEDIATE)?`)
+	// sqliteIndexPredicateRegexp matches predicate of partial index in create index script (e.g. ("email") WHERE "deletedAt" IS NULL)
+	sqliteIndexPredicateRegexp = regexp.MustCompile(`(?is)\)\s+WHERE\s+(.+)$`)
+)
+
+// SqliteController works with file database. Sqlite has no enum types, so enums are emulated with CHECK constraints.
+// Columns and constraints can't be altered in sqlite, so such changes are applied by rebuilding the table.
+type SqliteController struct {
+	db *sql.DB
+}
+
+func (s *SqliteController) createSnapshotColumn(property schema_model.Property, enumNames []string) (snapshotColumn, error) {
+	column := snapshotColumn{name: property.Name}
+
+	propertyType := property.Type
+	isOptional := strings.HasSuffix(propertyType, "?")
+	if isOptional {
+		propertyType = propertyType[0 : len(propertyType)-1]
+	}
+
+	if slices.Contains(enumNames, propertyType) {
+		column.dataType = "TEXT"
+		column.enumName = propertyType
+		column.nullable = isOptional
+	} else {
+		sqliteType, isValidType := property.GetSqliteType()
+		if !isValidType {
+			return snapshotColumn{}, database_error.DatabaseError{
+				ErrorType: database_error.SqlGenerationError,
+				Text:      fmt.Sprintf("Invalid property type provided: %s", property.Type),
+			}
+		}
+		column.dataType = strings.TrimSuffix(sqliteType, " NOT NULL")
+		column.nullable = !strings.HasSuffix(sqliteType, " NOT NULL")
+	}
+
+	switch property.Default {
+	case "":
+	case "autoincrement()":
+		column.autoincrement = true
+		column.nullable = false
+	case "uuid()":
+		column.defaultValue = sqliteUuidExpression
+	case "now()":
+		column.defaultValue = sqliteCurrentTimestamp
+	default:
+		value, err := property.ValidateDefaultValue()
+		if err != nil {
+			return snapshotColumn{}, err
+		}
+		if boolValue, isBool := value.(bool); isBool {
+			column.defaultValue = strconv.FormatBool(boolValue)
+		} else {
+			column.defaultValue = property.Default
+		}
+	}
+
+	return column, nil
+}
+
+func (s *SqliteController) createSnapshot(schema *schema_model.GoRelSchema, enumNames []string, modelNames []string) (databaseSnapshot, error) {
+	snapshot, err := createSnapshotFromSchema(schema, enumNames, modelNames, s.createSnapshotColumn)
+	if err != nil {
+		return databaseSnapshot{}, err
+	}
+
+	// AUTOINCREMENT can be used only with INTEGER PRIMARY KEY, other columns get values from rowid
+	for tableIndex := range snapshot.tables {
+		table := &snapshot.tables[tableIndex]
+		for columnIndex := range table.columns {
+			column := &table.columns[columnIndex]
+			if column.autoincrement && !s.isRowIdColumn(*table, *column) {
+				column.autoincrement = false
+			}
+		}
+	}
+
+	return snapshot, nil
+}
+
+// isRowIdColumn checks if column is the only primary key column of INTEGER type, which is an alias for rowid in sqlite
+func (s *SqliteController) isRowIdColumn(table snapshotTable, column snapshotColumn) bool {
+	return column.dataType == "INTEGER" && len(table.primaryKey.columns) == 1 && table.primaryKey.columns[0] == column.name
+}
+
+// sqliteTypeAffinities contains parts of declared sqlite types and schema types they are described with, sqlite determines type affinity the same way
+var sqliteTypeAffinities = []struct {
+	part     string
+	dataType string
+}{
+	{"INT", "INTEGER"},
+	{"BOOL", "BOOLEAN"},
+	{"DATE", "DATETIME"},
+	{"TIME", "DATETIME"},
+	{"CHAR", "TEXT"},
+	{"CLOB", "TEXT"},
+	{"TEXT", "TEXT"},
+	{"REAL", "REAL"},
+	{"FLOA", "REAL"},
+	{"DOUB", "REAL"},
+	{"NUMERIC", "REAL"},
+	{"DECIMAL", "REAL"},
+}
+
+func (s *SqliteController) createSchemaProperty(tableName string, column snapshotColumn) (schema_model.Property, *snapshotEnum, []string) {
+	property := schema_model.Property{Name: column.name}
+	var warnings []string
+
+	if column.enumName != "" {
+		property.Type = column.enumName
+		if column.nullable {
+			property.Type += "?"
+		}
+	} else {
+		dataType := column.dataType
+		if _, isFound := schema_model.PropertyTypeFromSqliteType(dataType, column.nullable); !isFound {
+			dataType = ""
+			for _, affinity := range sqliteTypeAffinities {
+				if strings.Contains(column.dataType, affinity.part) {
+					dataType = affinity.dataType
+					break
+				}
+			}
+			if dataType == "" {
+				dataType = "TEXT"
+			}
+			warnings = append(warnings, fmt.Sprintf("Type %s of column \"%s\" of table \"%s\" is not supported by the schema and is replaced with %s", column.dataType, column.name, tableName, dataType))
+		}
+
+		propertyType, _ := schema_model.PropertyTypeFromSqliteType(dataType, column.nullable)
+		property.Type = string(propertyType)
+	}
+
+	defaultValue := column.defaultValue
+	switch {
+	case column.autoincrement:
+		defaultValue = "autoincrement()"
+	case defaultValue == sqliteUuidExpression:
+		defaultValue = "uuid()"
+	case strings.ToUpper(defaultValue) == sqliteCurrentTimestamp:
+		defaultValue = "now()"
+	case property.Type == schema_model.Boolean && (defaultValue == "0" || defaultValue == "1"):
+		defaultValue = strconv.FormatBool(defaultValue == "1")
+	}
+	warnings = append(warnings, setPropertyDefault(&property, tableName, defaultValue)...)
+
+	return property, nil, warnings
+}
+
+func (s *SqliteController) getSnapshot() (databaseSnapshot, error) {
+	var snapshot databaseSnapshot
+
+	tables, scripts, err := s.getTables()
+	if err != nil {
+		return databaseSnapshot{}, database_error.DatabaseError{
+			ErrorType: database_error.IntrospectionError,
+			Text:      fmt.Sprintf("Can't get tables: %s", err),
+		}
+	}
+
+	enums := make(map[string][]string)
+
+	for _, tableName := range sortedKeys(tables) {
+		table := tables[tableName]
+
+		if err := s.getColumns(table, scripts[tableName], enums); err != nil {
+			return databaseSnapshot{}, database_error.DatabaseError{
+				ErrorType: database_error.IntrospectionError,
+				Text:      fmt.Sprintf("Can't get columns of table %s: %s", tableName, err),
+			}
+		}
+
+		if err := s.getIndexes(table); err != nil {
+			return databaseSnapshot{}, database_error.DatabaseError{
+				ErrorType: database_error.IntrospectionError,
+				Text:      fmt.Sprintf("Can't get indexes of table %s: %s", tableName, err),
+			}
+		}
+
+		if err := s.getForeignKeys(table, scripts[tableName]); err != nil {
+			return databaseSnapshot{}, database_error.DatabaseError{
+				ErrorType: database_error.IntrospectionError,
+				Text:      fmt.Sprintf("Can't get foreign keys of table %s: %s", tableName, err),
+			}
+		}
+
+		snapshot.tables = append(snapshot.tables, *table)
+	}
+
+	enumNames := make([]string, 0, len(enums))
+	for enumName := range enums {
+		enumNames = append(enumNames, enumName)
+	}
+	sort.Strings(enumNames)
+	for _, enumName := range enumNames {
+		snapshot.enums = append(snapshot.enums, snapshotEnum{name: enumName, values: enums[enumName]})
+	}
+
+	return snapshot, nil
+}
+
+// getRebuiltTables returns names of tables that can't be changed with ALTER TABLE and should be recreated
+func (s *SqliteController) getRebuiltTables(diff snapshotDiff) []string {
+	isRebuilt := make(map[string]bool)
+
+	isExistingTable := func(tableName string) bool {
+		_, exists := diff.current.findTable(tableName)
+		return exists
+	}
+
+	for _, column := range diff.droppedColumns {
+		isRebuilt[column.table] = true
+	}
+	for _, change := range diff.alteredColumns {
+		isRebuilt[change.table] = true
+	}
+	for _, column := range diff.addedColumns {
+		if !s.canAddColumn(column.table, column.column, diff) {
+			isRebuilt[column.table] = true
+		}
+	}
+	for _, primaryKey := range diff.addedPrimaryKeys {
+		isRebuilt[primaryKey.table] = true
+	}
+	for _, primaryKey := range diff.droppedPrimaryKeys {
+		isRebuilt[primaryKey.table] = true
+	}
+	for _, foreignKey := range diff.addedForeignKeys {
+		if isExistingTable(foreignKey.table) {
+			isRebuilt[foreignKey.table] = true
+		}
+	}
+	for _, foreignKey := range diff.droppedForeignKeys {
+		isRebuilt[foreignKey.table] = true
+	}
+	for _, change := range diff.alteredEnums {
+		for _, table := range diff.target.tables {
+			usesEnum := slices.ContainsFunc(table.columns, func(c snapshotColumn) bool { return c.enumName == change.target.name })
+			if usesEnum && isExistingTable(table.name) {
+				isRebuilt[table.name] = true
+			}
+		}
+	}
+
+	var tableNames []string
+	for _, table := range diff.target.tables {
+		if isRebuilt[table.name] {
+			tableNames = append(tableNames, table.name)
+		}
+	}
+	return tableNames
+}
+
+// canAddColumn checks if column can be added with ALTER TABLE ADD COLUMN. Sqlite can't add primary key columns,
+// columns with non-constant default values and required columns without default values
+func (s *SqliteController) canAddColumn(tableName string, column snapshotColumn, diff snapshotDiff) bool {
+	if column.autoincrement || s.isDefaultExpression(column.defaultValue) {
+		return false
+	}
+	if !column.nullable && column.defaultValue == "" {
+		return false
+	}
+	table, _ := diff.target.findTable(tableName)
+	return !slices.Contains(table.primaryKey.columns, column.name)
+}
+
+func (s *SqliteController) generateMigrationSteps(diff snapshotDiff) []MigrationStep {
+	var steps []MigrationStep
+
+	rebuiltTables := s.getRebuiltTables(diff)
+
+	for _, unique := range diff.droppedUniques {
+		if slices.Contains(rebuiltTables, unique.table) {
+			continue
+		}
+		steps = append(steps, MigrationStep{Query: s.generateDropIndexSqlScript(unique.constraint.name)})
+	}
+
+	for _, index := range diff.droppedIndexes {
+		if slices.Contains(rebuiltTables, index.table) {
+			continue
+		}
+		steps = append(steps, MigrationStep{Query: s.generateDropIndexSqlScript(index.index.name)})
+	}
+
+	for _, table := range diff.droppedTables {
+		steps = append(steps, MigrationStep{
+			Query:   s.generateDeleteTableSqlScriptFromDbTableName(table.name),
+			Warning: fmt.Sprintf("Table \"%s\" will be dropped with all of its data", table.name),
+		})
+	}
+
+	for _, table := range diff.createdTables {
+		steps = append(steps, MigrationStep{Query: s.generateCreateTableSqlScriptFromTable(table.name, table, diff.target.enums)})
+		for _, unique := range table.uniques {
+			steps = append(steps, MigrationStep{Query: s.generateCreateUniqueIndexSqlScript(table.name, unique)})
+		}
+	}
+
+	for _, column := range diff.addedColumns {
+		if slices.Contains(rebuiltTables, column.table) {
+			continue
+		}
+		steps = append(steps, MigrationStep{Query: s.generateAddColumnSqlScript(column.table, column.column, diff.target.enums)})
+	}
+
+	for _, tableName := range rebuiltTables {
+		steps = append(steps, s.generateRebuildTableSqlScripts(tableName, diff)...)
+	}
+
+	for _, unique := range diff.addedUniques {
+		if slices.Contains(rebuiltTables, unique.table) {
+			continue
+		}
+		steps = append(steps, MigrationStep{
+			Query:   s.generateCreateUniqueIndexSqlScript(unique.table, unique.constraint),
+			Warning: fmt.Sprintf("Unique index \"%s\" is added to table \"%s\". Migration will fail if there are duplicate values", unique.constraint.name, unique.table),
+		})
+	}
+
+	for _, index := range diff.addedIndexes {
+		if slices.Contains(rebuiltTables, index.table) {
+			continue
+		}
+		step := MigrationStep{Query: s.generateCreateIndexSqlScript(index.table, index.index)}
+		if index.index.unique {
+			step.Warning = fmt.Sprintf("Unique index \"%s\" is added to table \"%s\". Migration will fail if there are duplicate values", index.index.name, index.table)
+		}
+		steps = append(steps, step)
+	}
+
+	return steps
+}
+
+func (s *SqliteController) createMigrationsTable() error {
+	/*
+		CREATE TABLE IF NOT EXISTS "_gorel_migrations" (
+			"name" TEXT NOT NULL PRIMARY KEY,
+			"checksum" TEXT NOT NULL,
+			"started_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			"finished_at" DATETIME,
+			"failed" BOOLEAN NOT NULL DEFAULT false
+		);
+	*/
+	rawSqlString := fmt.Sprintf("CREATE TABLE IF NOT EXISTS \"%s\" (\"name\" TEXT NOT NULL PRIMARY KEY, \"checksum\" TEXT NOT NULL, \"started_at\" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP, \"finished_at\" DATETIME, \"failed\" BOOLEAN NOT NULL DEFAULT false);", MigrationsTableName)
+	if _, err := s.db.Exec(rawSqlString); err != nil {
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't create migrations table: %s", err),
+		}
+	}
+	return nil
+}
+
+func (s *SqliteController) migrationsTableExists() (bool, error) {
+	var count int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM \"sqlite_master\" WHERE \"type\" = 'table' AND \"name\" = ?", MigrationsTableName).Scan(&count); err != nil {
+		return false, database_error.DatabaseError{
+			ErrorType: database_error.IntrospectionError,
+			Text:      fmt.Sprintf("Can't check if migrations table exists: %s", err),
+		}
+	}
+	return count != 0, nil
+}
+
+// GetAppliedMigrations returns records of the migrations table. It does not create the table, so database is not changed.
+func (s *SqliteController) GetAppliedMigrations() ([]AppliedMigration, error) {
+	exists, err := s.migrationsTableExists()
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	rows, err := s.db.Query(fmt.Sprintf("SELECT \"name\", \"checksum\", \"started_at\", \"finished_at\", \"failed\" FROM \"%s\" ORDER BY \"name\"", MigrationsTableName))
+	if err != nil {
+		return nil, database_error.DatabaseError{
+			ErrorType: database_error.IntrospectionError,
+			Text:      fmt.Sprintf("Can't get applied migrations: %s", err),
+		}
+	}
+	defer rows.Close()
+
+	var migrations []AppliedMigration
+	for rows.Next() {
+		var migration AppliedMigration
+		if err := rows.Scan(&migration.Name, &migration.Checksum, &migration.StartedAt, &migration.FinishedAt, &migration.Failed); err != nil {
+			return nil, database_error.DatabaseError{
+				ErrorType: database_error.IntrospectionError,
+				Text:      fmt.Sprintf("Can't get applied migrations: %s", err),
+			}
+		}
+		migrations = append(migrations, migration)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, database_error.DatabaseError{
+			ErrorType: database_error.IntrospectionError,
+			Text:      fmt.Sprintf("Can't get applied migrations: %s", err),
+		}
+	}
+
+	return migrations, nil
+}
+
+func (s *SqliteController) markMigrationAsFailed(name string) {
+	if _, err := s.db.Exec(fmt.Sprintf("UPDATE \"%s\" SET \"failed\" = true, \"finished_at\" = CURRENT_TIMESTAMP WHERE \"name\" = ?", MigrationsTableName), name); err != nil {
+		fmt.Println(fmt.Sprintf("Can't mark migration %s as failed: %s", name, err))
+	}
+}
+
+// checkForeignKeys returns error if some rows reference missing records. It is needed, because foreign keys are disabled while script runs.
+func (s *SqliteController) checkForeignKeys(tx *sql.Tx) error {
+	rows, err := tx.Query("PRAGMA foreign_key_check")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var tableName, referenceTable string
+		var rowId sql.NullInt64
+		var foreignKeyId int
+		if err := rows.Scan(&tableName, &rowId, &referenceTable, &foreignKeyId); err != nil {
+			return err
+		}
+		return errors.New(fmt.Sprintf("rows of table %s reference missing records of table %s", tableName, referenceTable))
+	}
+
+	return rows.Err()
+}
+
+// executeScript runs script in transaction with disabled foreign keys, so tables can be dropped and recreated in any order.
+// Foreign keys are checked before commit. finish is called in the same transaction after script.
+func (s *SqliteController) executeScript(script string, finish func(tx *sql.Tx) error) error {
+	// foreign_keys pragma is ignored inside transaction, so it is changed before transaction starts
+	if _, err := s.db.Exec("PRAGMA foreign_keys = OFF"); err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = s.db.Exec("PRAGMA foreign_keys = ON")
+	}()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(script); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := s.checkForeignKeys(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := finish(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *SqliteController) ApplyMigration(name string, checksum string, script string) error {
+	if err := s.createMigrationsTable(); err != nil {
+		return err
+	}
+
+	// Migration record is saved outside of transaction, so it is kept if migration fails
+	startMigrationQuery := fmt.Sprintf("INSERT INTO \"%s\" (\"name\", \"checksum\") VALUES (?, ?) ON CONFLICT (\"name\") DO UPDATE SET \"checksum\" = excluded.\"checksum\", \"started_at\" = CURRENT_TIMESTAMP, \"finished_at\" = NULL, \"failed\" = false", MigrationsTableName)
+	if _, err := s.db.Exec(startMigrationQuery, name, checksum); err != nil {
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't save migration %s: %s", name, err),
+		}
+	}
+
+	if err := s.executeScript(script, func(tx *sql.Tx) error {
+		_, err := tx.Exec(fmt.Sprintf("UPDATE \"%s\" SET \"finished_at\" = CURRENT_TIMESTAMP WHERE \"name\" = ?", MigrationsTableName), name)
+		return err
+	}); err != nil {
+		s.markMigrationAsFailed(name)
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't apply migration %s: %s", name, err),
+		}
+	}
+
+	return nil
+}
+
+func (s *SqliteController) RollbackMigration(name string, script string) error {
+	if err := s.executeScript(script, func(tx *sql.Tx) error {
+		_, err := tx.Exec(fmt.Sprintf("DELETE FROM \"%s\" WHERE \"name\" = ?", MigrationsTableName), name)
+		return err
+	}); err != nil {
+		return database_error.DatabaseError{
+			ErrorType: database_error.TransactionError,
+			Text:      fmt.Sprintf("Can't rollback migration %s: %s", name, err),
+		}
+	}
+
+	return nil
+}
+
+func (s *SqliteController) Close() error {
+	if err := s.db.Close(); err != nil {
+		return database_error.DatabaseError{
+			ErrorType: database_error.CloseConnectionError,
+			Text:      fmt.Sprintf("Can't close connection to sqlite db."),
+		}
+	}
+	return nil
+}
+
+func (s *SqliteController) getTables() (tables map[string]*snapshotTable, scripts map[string]string, err error) {
+	/*
+		SELECT "name", "sql"
+		FROM "sqlite_master"
+		WHERE "type" = 'table' AND "name" NOT LIKE 'sqlite_%' AND "name" <> '_gorel_migrations';
+	*/
+	const rawSqlString = "SELECT \"name\", \"sql\" FROM \"sqlite_master\" WHERE \"type\" = 'table' AND \"name\" NOT LIKE 'sqlite_%' AND \"name\" <> ?"
+
+	rows, err := s.db.Query(rawSqlString, MigrationsTableName)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	tables = make(map[string]*snapshotTable)
+	scripts = make(map[string]string)
+	for rows.Next() {
+		var tableName, script string
+		if err := rows.Scan(&tableName, &script); err != nil {
+			return nil, nil, err
+		}
+		tables[tableName] = &snapshotTable{name: tableName}
+		scripts[tableName] = script
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return tables, scripts, nil
+}
+
+// getColumns reads columns and primary key of the table. Enum columns are found by CHECK constraints in create table script,
+// values of found enums are added to enums map.
+func (s *SqliteController) getColumns(table *snapshotTable, script string, enums map[string][]string) error {
+	const rawSqlString = "SELECT \"name\", \"type\", \"notnull\", \"dflt_value\", \"pk\" FROM pragma_table_info(?) ORDER BY \"cid\""
+
+	rows, err := s.db.Query(rawSqlString, table.name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	primaryKeyColumns := make(map[int]string)
+	for rows.Next() {
+		var columnName, dataType string
+		var notNull bool
+		var defaultValue sql.NullString
+		var primaryKeyIndex int
+		if err := rows.Scan(&columnName, &dataType, &notNull, &defaultValue, &primaryKeyIndex); err != nil {
+			return err
+		}
+
+		table.columns = append(table.columns, snapshotColumn{
+			name:         columnName,
+			dataType:     strings.ToUpper(dataType),
+			nullable:     !notNull,
+			defaultValue: s.normalizeDefaultValue(defaultValue.String),
+		})
+		if primaryKeyIndex > 0 {
+			primaryKeyColumns[primaryKeyIndex] = columnName
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(primaryKeyColumns) != 0 {
+		table.primaryKey.name = fmt.Sprintf("%s_pkey", table.name)
+		for index := 1; index <= len(primaryKeyColumns); index++ {
+			table.primaryKey.columns = append(table.primaryKey.columns, primaryKeyColumns[index])
+		}
+	}
+
+	for index := range table.columns {
+		column := &table.columns[index]
+		if s.isRowIdColumn(*table, *column) && strings.Contains(strings.ToUpper(script), "AUTOINCREMENT") {
+			column.autoincrement = true
+		}
+	}
+
+	for _, matches := range sqliteEnumCheckRegexp.FindAllStringSubmatch(script, -1) {
+		enumName, columnName := matches[1], matches[2]
+		for index := range table.columns {
+			if table.columns[index].name == columnName {
+				table.columns[index].enumName = enumName
+			}
+		}
+		if _, exists := enums[enumName]; exists {
+			continue
+		}
+		var values []string
+		for _, valueMatches := range sqliteEnumValueRegexp.FindAllStringSubmatch(matches[3], -1) {
+			values = append(values, strings.ReplaceAll(valueMatches[1], "''", "'"))
+		}
+		enums[enumName] = values
+	}
+
+	return nil
+}
+
+// getIndexes reads indexes created with CREATE INDEX. Unique indexes without options are unique constraints of the table.
+func (s *SqliteController) getIndexes(table *snapshotTable) error {
+	/*
+		SELECT il."name", il."unique", COALESCE(m."sql", '')
+		FROM pragma_index_list(?) il
+		LEFT JOIN "sqlite_master" m ON m."type" = 'index' AND m."name" = il."name"
+		WHERE il."origin" = 'c'
+		ORDER BY il."name";
+	*/
+	const rawSqlString = "SELECT il.\"name\", il.\"unique\", COALESCE(m.\"sql\", '') FROM pragma_index_list(?) il LEFT JOIN \"sqlite_master\" m ON m.\"type\" = 'index' AND m.\"name\" = il.\"name\" WHERE il.\"origin\" = 'c' ORDER BY il.\"name\""
+
+	rows, err := s.db.Query(rawSqlString, table.name)
+	if err != nil {
+		return err
+	}
+
+	var indexes []snapshotIndex
+	for rows.Next() {
+		var index snapshotIndex
+		var script string
+		if err := rows.Scan(&index.name, &index.unique, &script); err != nil {
+			rows.Close()
+			return err
+		}
+		if matches := sqliteIndexPredicateRegexp.FindStringSubmatch(script); matches != nil {
+			index.where = strings.TrimSpace(matches[1])
+		}
+		indexes = append(indexes, index)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Only one connection is used, so columns are read after index list is closed
+	for _, index := range indexes {
+		if err := s.getIndexColumns(&index); err != nil {
+			return err
+		}
+		if index.isUniqueConstraint() {
+			table.uniques = append(table.uniques, snapshotConstraint{name: index.name, columns: index.columns})
+			continue
+		}
+		table.indexes = append(table.indexes, index)
+	}
+
+	return nil
+}
+
+// getIndexColumns reads key columns of the index and their sort order
+func (s *SqliteController) getIndexColumns(index *snapshotIndex) error {
+	const rawSqlString = "SELECT \"name\", \"desc\" FROM pragma_index_xinfo(?) WHERE \"key\" = 1 ORDER BY \"seqno\""
+
+	rows, err := s.db.Query(rawSqlString, index.name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var column string
+		var isDescending bool
+		if err := rows.Scan(&column, &isDescending); err != nil {
+			return err
+		}
+		index.columns = append(index.columns, column)
+		if isDescending {
+			index.descending = append(index.descending, column)
+		}
+	}
+
+	return rows.Err()
+}
+
+// getForeignKeys reads foreign keys of the table. Sqlite doesn't store names of constraints, so they are taken from create table script.
+func (s *SqliteController) getForeignKeys(table *snapshotTable, script string) error {
+	const rawSqlString = "SELECT \"id\", \"table\", \"from\", \"to\", \"on_delete\", \"on_update\" FROM pragma_foreign_key_list(?) ORDER BY \"id\", \"seq\""
+
+	rows, err := s.db.Query(rawSqlString, table.name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var foreignKeys []snapshotForeignKey
+	lastId := -1
+	for rows.Next() {
+		var id int
+		var referenceTable, column, onDelete, onUpdate string
+		var referenceColumn sql.NullString
+		if err := rows.Scan(&id, &referenceTable, &column, &referenceColumn, &onDelete, &onUpdate); err != nil {
+			return err
+		}
+		if id != lastId {
+			foreignKeys = append(foreignKeys, snapshotForeignKey{
+				referenceTable: referenceTable,
+				onDelete:       normalizeReferentialAction(onDelete),
+				onUpdate:       normalizeReferentialAction(onUpdate),
+			})
+			lastId = id
+		}
+		foreignKey := &foreignKeys[len(foreignKeys)-1]
+		foreignKey.columns = append(foreignKey.columns, column)
+		foreignKey.referenceColumns = append(foreignKey.referenceColumns, referenceColumn.String)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, matches := range sqliteForeignKeyRegexp.FindAllStringSubmatch(script, -1) {
+		columns := s.parseColumnList(matches[2])
+		for index := range foreignKeys {
+			foreignKey := &foreignKeys[index]
+			if foreignKey.name == "" && foreignKey.referenceTable == matches[3] && slices.Equal(foreignKey.columns, columns) {
+				foreignKey.name = matches[1]
+				foreignKey.deferrable = matches[6] != ""
+				break
+			}
+		}
+	}
+
+	table.foreignKeys = append(table.foreignKeys, foreignKeys...)
+	return nil
+}
+
+func (s *SqliteController) parseColumnList(columnList string) []string {
+	var columns []string
+	for _, column := range strings.Split(columnList, ",") {
+		columns = append(columns, strings.Trim(strings.TrimSpace(column), "\""))
+	}
+	return columns
+}
+
+// normalizeDefaultValue converts default expression returned by sqlite to the form used in snapshots
+func (s *SqliteController) normalizeDefaultValue(expression string) string {
+	if strings.HasPrefix(expression, "'") && strings.HasSuffix(expression, "'") && len(expression) > 1 {
+		return strings.ReplaceAll(expression[1:len(expression)-1], "''", "'")
+	}
+	return expression
+}
+
+func (s *SqliteController) checkConnection() error {
+	if err := s.db.Ping(); err != nil {
+		return database_error.DatabaseError{
+			ErrorType: database_error.ConnectionError,
+			Text:      fmt.Sprintf("Can't connect to db: %s", err),
+		}
+	}
+	return nil
+}
+
+func (s *SqliteController) isDefaultExpression(defaultValue string) bool {
+	return defaultValue == sqliteUuidExpression || defaultValue == sqliteCurrentTimestamp
+}
+
+func (s *SqliteController) generateDefaultValue(column snapshotColumn) string {
+	switch {
+	case column.defaultValue == sqliteCurrentTimestamp:
+		return column.defaultValue
+	case s.isDefaultExpression(column.defaultValue):
+		return fmt.Sprintf("(%s)", column.defaultValue)
+	case column.enumName != "" || column.dataType == "TEXT" || column.dataType == "DATETIME":
+		return fmt.Sprintf("'%s'", strings.ReplaceAll(column.defaultValue, "'", "''"))
+	default:
+		return column.defaultValue
+	}
+}
+
+func (s *SqliteController) generateEnumCheck(column snapshotColumn, enums []snapshotEnum) string {
+	//CONSTRAINT "UserRole" CHECK ("role" IN ('Admin', 'User'))
+	var values []string
+	for _, enum := range enums {
+		if enum.name != column.enumName {
+			continue
+		}
+		for _, value := range enum.values {
+			values = append(values, fmt.Sprintf("'%s'", strings.ReplaceAll(value, "'", "''")))
+		}
+	}
+	return fmt.Sprintf("CONSTRAINT \"%s\" CHECK (\"%s\" IN (%s))", column.enumName, column.name, strings.Join(values, ", "))
+}
+
+func (s *SqliteController) generateColumnDefinition(column snapshotColumn, enums []snapshotEnum) string {
+	//"id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT
+	if column.autoincrement {
+		return fmt.Sprintf("\"%s\" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT", column.name)
+	}
+
+	definition := fmt.Sprintf("\"%s\" %s", column.name, column.dataType)
+	if !column.nullable {
+		definition += " NOT NULL"
+	}
+	if column.defaultValue != "" {
+		definition += fmt.Sprintf(" DEFAULT %s", s.generateDefaultValue(column))
+	}
+	if column.enumName != "" {
+		definition += " " + s.generateEnumCheck(column, enums)
+	}
+	return definition
+}
+
+func (s *SqliteController) generateColumnList(columns []string) string {
+	quotedColumns := make([]string, len(columns))
+	for index, column := range columns {
+		quotedColumns[index] = fmt.Sprintf("\"%s\"", column)
+	}
+	return strings.Join(quotedColumns, ", ")
+}
+
+func (s *SqliteController) generateForeignKeyDefinition(foreignKey snapshotForeignKey) string {
+	//CONSTRAINT "fk_User" FOREIGN KEY ("userId") REFERENCES "User" ("id") ON DELETE CASCADE
+	definition := fmt.Sprintf("CONSTRAINT \"%s\" FOREIGN KEY (%s) REFERENCES \"%s\" (%s)", foreignKey.name, s.generateColumnList(foreignKey.columns), foreignKey.referenceTable, s.generateColumnList(foreignKey.referenceColumns))
+	definition += generateReferentialActions(foreignKey)
+	if foreignKey.deferrable {
+		definition += " DEFERRABLE INITIALLY IMMEDIATE"
+	}
+	return definition
+}
+
+// generateCreateTableSqlScriptFromTable creates table with the given name, so it can be used to create temporary table while table is rebuilt
+func (s *SqliteController) generateCreateTableSqlScriptFromTable(tableName string, table snapshotTable, enums []snapshotEnum) string {
+	//CREATE TABLE "Todo" ("id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "userId" INTEGER NOT NULL, CONSTRAINT "fk_User" FOREIGN KEY ("userId") REFERENCES "User" ("id"));
+	var definitions []string
+	hasRowIdColumn := false
+	for _, column := range table.columns {
+		definitions = append(definitions, s.generateColumnDefinition(column, enums))
+		if column.autoincrement {
+			hasRowIdColumn = true
+		}
+	}
+	if len(table.primaryKey.columns) != 0 && !hasRowIdColumn {
+		definitions = append(definitions, fmt.Sprintf("CONSTRAINT \"%s\" PRIMARY KEY (%s)", table.primaryKey.name, s.generateColumnList(table.primaryKey.columns)))
+	}
+	for _, foreignKey := range table.foreignKeys {
+		definitions = append(definitions, s.generateForeignKeyDefinition(foreignKey))
+	}
+	return fmt.Sprintf("CREATE TABLE \"%s\" (\n\t%s\n);", tableName, strings.Join(definitions, ",\n\t"))
+}
+
+func (s *SqliteController) generateDeleteTableSqlScriptFromDbTableName(tableName string) string {
+	//DROP TABLE "User";
+	return fmt.Sprintf("DROP TABLE \"%s\";", tableName)
+}
+
+func (s *SqliteController) generateAddColumnSqlScript(tableName string, column snapshotColumn, enums []snapshotEnum) string {
+	//ALTER TABLE "User" ADD COLUMN "email" TEXT;
+	return fmt.Sprintf("ALTER TABLE \"%s\" ADD COLUMN %s;", tableName, s.generateColumnDefinition(column, enums))
+}
+
+func (s *SqliteController) generateCreateUniqueIndexSqlScript(tableName string, unique snapshotConstraint) string {
+	//CREATE UNIQUE INDEX "User_email_key" ON "User" ("email");
+	return fmt.Sprintf("CREATE UNIQUE INDEX \"%s\" ON \"%s\" (%s);", unique.name, tableName, s.generateColumnList(unique.columns))
+}
+
+func (s *SqliteController) generateCreateIndexSqlScript(tableName string, index snapshotIndex) string {
+	//CREATE INDEX "Post_authorId_createdAt_idx" ON "Post" ("authorId", "createdAt" DESC) WHERE "deletedAt" IS NULL;
+	rawSqlString := "CREATE INDEX"
+	if index.unique {
+		rawSqlString = "CREATE UNIQUE INDEX"
+	}
+
+	columns := make([]string, len(index.columns))
+	for columnIndex, column := range index.columns {
+		columns[columnIndex] = fmt.Sprintf("\"%s\"", column)
+		if slices.Contains(index.descending, column) {
+			columns[columnIndex] += " DESC"
+		}
+	}
+	rawSqlString += fmt.Sprintf(" \"%s\" ON \"%s\" (%s)", index.name, tableName, strings.Join(columns, ", "))
+
+	if index.where != "" {
+		rawSqlString += fmt.Sprintf(" WHERE %s", index.where)
+	}
+	return rawSqlString + ";"
+}
+
+func (s *SqliteController) generateDropIndexSqlScript(indexName string) string {
+	//DROP INDEX "User_email_key";
+	return fmt.Sprintf("DROP INDEX \"%s\";", indexName)
+}
+
+// getRebuildWarnings describes changes of the rebuilt table that can lead to data loss or fail on existing rows
+func (s *SqliteController) getRebuildWarnings(current snapshotTable, target snapshotTable, diff snapshotDiff) []string {
+	var warnings []string
+
+	for _, column := range current.columns {
+		if _, exists := target.findColumn(column.name); !exists {
+			warnings = append(warnings, fmt.Sprintf("column \"%s\" will be dropped with all of its data", column.name))
+		}
+	}
+
+	for _, column := range target.columns {
+		currentColumn, exists := current.findColumn(column.name)
+		if !exists {
+			if !column.nullable && column.defaultValue == "" && !column.autoincrement {
+				warnings = append(warnings, fmt.Sprintf("required column \"%s\" without default value is added, migration will fail if the table is not empty", column.name))
+			}
+			continue
+		}
+		if currentColumn.dataType != column.dataType {
+			warnings = append(warnings, fmt.Sprintf("type of column \"%s\" will be changed from %s to %s", column.name, currentColumn.dataType, column.dataType))
+		}
+		if currentColumn.nullable && !column.nullable {
+			warnings = append(warnings, fmt.Sprintf("column \"%s\" becomes required, migration will fail if it contains NULL values", column.name))
+		}
+		if column.enumName != "" {
+			currentEnum, _ := diff.current.findEnum(currentColumn.enumName)
+			targetEnum, _ := diff.target.findEnum(column.enumName)
+			isNarrowed := currentColumn.enumName == "" || slices.ContainsFunc(currentEnum.values, func(value string) bool { return !slices.Contains(targetEnum.values, value) })
+			if isNarrowed {
+				warnings = append(warnings, fmt.Sprintf("column \"%s\" accepts only values of enum \"%s\", migration will fail if it contains other values", column.name, column.enumName))
+			}
+		}
+	}
+
+	for _, unique := range diff.addedUniques {
+		if unique.table == target.name {
+			warnings = append(warnings, fmt.Sprintf("unique index \"%s\" is added, migration will fail if there are duplicate values", unique.constraint.name))
+		}
+	}
+
+	for _, index := range diff.addedIndexes {
+		if index.table == target.name && index.index.unique {
+			warnings = append(warnings, fmt.Sprintf("unique index \"%s\" is added, migration will fail if there are duplicate values", index.index.name))
+		}
+	}
+
+	for _, foreignKey := range diff.addedForeignKeys {
+		if foreignKey.table == target.name {
+			warnings = append(warnings, fmt.Sprintf("foreign key \"%s\" is added, migration will fail if rows reference missing records", foreignKey.foreignKey.name))
+		}
+	}
+
+	return warnings
+}
+
+// generateRebuildTableSqlScripts recreates table, because sqlite can't alter columns and constraints.
+// Data is copied to the new table with target structure, then old table is dropped and new one is renamed.
+func (s *SqliteController) generateRebuildTableSqlScripts(tableName string, diff snapshotDiff) []MigrationStep {
+	current, _ := diff.current.findTable(tableName)
+	target, _ := diff.target.findTable(tableName)
+	newTableName := sqliteRebuildTablePrefix + tableName
+
+	createStep := MigrationStep{Query: s.generateCreateTableSqlScriptFromTable(newTableName, target, diff.target.enums)}
+	if warnings := s.getRebuildWarnings(current, target, diff); len(warnings) != 0 {
+		createStep.Warning = fmt.Sprintf("Table \"%s\" will be recreated: %s", tableName, strings.Join(warnings, "; "))
+	}
+	steps := []MigrationStep{createStep}
+
+	var copiedColumns []string
+	for _, column := range target.columns {
+		if _, exists := current.findColumn(column.name); exists {
+			copiedColumns = append(copiedColumns, column.name)
+		}
+	}
+	if len(copiedColumns) != 0 {
+		//INSERT INTO "_gorel_new_User" ("id", "email") SELECT "id", "email" FROM "User";
+		columnList := s.generateColumnList(copiedColumns)
+		steps = append(steps, MigrationStep{Query: fmt.Sprintf("INSERT INTO \"%s\" (%s) SELECT %s FROM \"%s\";", newTableName, columnList, columnList, tableName)})
+	}
+
+	steps = append(steps,
+		MigrationStep{Query: s.generateDeleteTableSqlScriptFromDbTableName(tableName)},
+		MigrationStep{Query: fmt.Sprintf("ALTER TABLE \"%s\" RENAME TO \"%s\";", newTableName, tableName)},
+	)
+
+	for _, unique := range target.uniques {
+		steps = append(steps, MigrationStep{Query: s.generateCreateUniqueIndexSqlScript(tableName, unique)})
+	}
+	for _, index := range target.indexes {
+		steps = append(steps, MigrationStep{Query: s.generateCreateIndexSqlScript(tableName, index)})
+	}
+
+	return steps
+}
@@ -0,0 +1,71 @@
+package database_contoller
+
+import (
+	"GoRelCli/models/schema_model"
+	"path/filepath"
+	"testing"
+)
+
+func TestSqliteIndexRoundTrip(t *testing.T) {
+	offlineController, target := testSnapshot(t, schema_model.SQLite, `
+models:
+  - name: Author
+    properties:
+      - name: id
+        type: int
+        default: autoincrement()
+        id: true
+      - name: posts
+        type: Post[]
+  - name: Post
+    properties:
+      - name: id
+        type: int
+        default: autoincrement()
+        id: true
+      - name: authorId
+        type: int
+      - name: author
+        type: Author
+        relationField: authorId
+        referenceField: id
+        onDelete: cascade
+      - name: status
+        type: string
+      - name: title
+        type: string
+      - name: deletedAt
+        type: dateTime?
+    indexes:
+      - fields: [{name: status}, {name: title}]
+        unique: true
+      - fields: [{name: title}]
+        where: '"deletedAt" IS NULL'
+      - name: Post_status_active_key
+        fields: [{name: status}]
+        unique: true
+        where: status = 'active'
+      - name: Post_recent_idx
+        fields: [{name: deletedAt, sort: desc}, {name: id}]
+      - name: Post_title_desc_key
+        fields: [{name: title, sort: desc}]
+        unique: true
+`)
+
+	controller, err := getSqliteDatabaseController(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer controller.Close()
+
+	steps := offlineController.generateMigrationSteps(diffSnapshots(databaseSnapshot{}, target))
+	if err := controller.ApplyMigration("init", "", GenerateSqlScript(steps)); err != nil {
+		t.Fatal(err)
+	}
+	introspected, err := controller.getSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertRoundTrip(t, controller, schema_model.SQLite, target, introspected)
+}
@@ -0,0 +1,15 @@
+CREATE TABLE `User` (
+	`id` int NOT NULL AUTO_INCREMENT,
+	PRIMARY KEY (`id`)
+) DEFAULT CHARACTER SET utf8mb4;
+CREATE TABLE `Video` (
+	`id` varchar(191) NOT NULL,
+	PRIMARY KEY (`id`)
+) DEFAULT CHARACTER SET utf8mb4;
+CREATE TABLE `UserToVideo` (
+	`userId` int NOT NULL,
+	`videoId` varchar(191) NOT NULL,
+	PRIMARY KEY (`userId`, `videoId`)
+) DEFAULT CHARACTER SET utf8mb4;
+ALTER TABLE `UserToVideo` ADD CONSTRAINT `UserToVideo_userId_fkey` FOREIGN KEY (`userId`) REFERENCES `User` (`id`) ON DELETE CASCADE;
+ALTER TABLE `UserToVideo` ADD CONSTRAINT `UserToVideo_videoId_fkey` FOREIGN KEY (`videoId`) REFERENCES `Video` (`id`);
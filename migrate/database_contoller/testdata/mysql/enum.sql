@@ -0,0 +1,6 @@
+CREATE TABLE `User` (
+	`id` int NOT NULL AUTO_INCREMENT,
+	`role` enum('user','admin','super_user') NOT NULL,
+	`previousRole` enum('user','admin','super_user'),
+	PRIMARY KEY (`id`)
+) DEFAULT CHARACTER SET utf8mb4;
@@ -0,0 +1,4 @@
+-- Warning: Type of column "role" of table "User" will be changed from enum('user','admin','super_user') to enum('user','moderator','admin','super_user'). Migration will fail if existing values can't be converted
+ALTER TABLE `User` MODIFY COLUMN `role` enum('user','moderator','admin','super_user') NOT NULL;
+-- Warning: Type of column "previousRole" of table "User" will be changed from enum('user','admin','super_user') to enum('user','moderator','admin','super_user'). Migration will fail if existing values can't be converted
+ALTER TABLE `User` MODIFY COLUMN `previousRole` enum('user','moderator','admin','super_user');
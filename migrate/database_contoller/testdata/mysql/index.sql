@@ -0,0 +1,9 @@
+CREATE TABLE `Post` (
+	`id` int NOT NULL AUTO_INCREMENT,
+	`authorName` varchar(191) NOT NULL,
+	`title` varchar(191) NOT NULL,
+	`publishedAt` datetime(6),
+	PRIMARY KEY (`id`),
+	UNIQUE INDEX `Post_authorName_title_key` (`authorName`, `title`)
+) DEFAULT CHARACTER SET utf8mb4;
+ALTER TABLE `Post` ADD INDEX `Post_published_idx` (`publishedAt` DESC, `id`);
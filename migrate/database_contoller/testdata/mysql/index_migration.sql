@@ -0,0 +1,6 @@
+ALTER TABLE `Post` DROP INDEX `Post_authorName_title_key`;
+ALTER TABLE `Post` DROP INDEX `Post_published_idx`;
+-- Warning: Type of column "title" of table "Post" will be changed from varchar(191) to text. Migration will fail if existing values can't be converted
+ALTER TABLE `Post` MODIFY COLUMN `title` text;
+ALTER TABLE `Post` ADD INDEX `Post_authorName_idx` (`authorName`);
+ALTER TABLE `Post` ADD INDEX `Post_published_idx` (`publishedAt`, `id`);
@@ -0,0 +1,6 @@
+ALTER TABLE `UserToVideo` DROP FOREIGN KEY `UserToVideo_videoId_fkey`;
+-- Warning: Type of column "id" of table "Video" will be changed from varchar(191) to int. Migration will fail if existing values can't be converted
+ALTER TABLE `Video` MODIFY COLUMN `id` int NOT NULL;
+-- Warning: Type of column "videoId" of table "UserToVideo" will be changed from varchar(191) to int. Migration will fail if existing values can't be converted
+ALTER TABLE `UserToVideo` MODIFY COLUMN `videoId` int NOT NULL;
+ALTER TABLE `UserToVideo` ADD CONSTRAINT `UserToVideo_videoId_fkey` FOREIGN KEY (`videoId`) REFERENCES `Video` (`id`) ON DELETE CASCADE;
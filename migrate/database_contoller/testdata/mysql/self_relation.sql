@@ -0,0 +1,6 @@
+CREATE TABLE `Employee` (
+	`id` varchar(191) NOT NULL DEFAULT (uuid()),
+	`managerId` varchar(191),
+	PRIMARY KEY (`id`)
+) DEFAULT CHARACTER SET utf8mb4;
+ALTER TABLE `Employee` ADD CONSTRAINT `Employee_managerId_fkey` FOREIGN KEY (`managerId`) REFERENCES `Employee` (`id`) ON DELETE SET NULL;
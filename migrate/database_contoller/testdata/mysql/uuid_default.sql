@@ -0,0 +1,9 @@
+CREATE TABLE `Note` (
+	`id` varchar(191) NOT NULL DEFAULT (uuid()),
+	`slug` varchar(191) NOT NULL,
+	`text` text NOT NULL,
+	`title` varchar(191) NOT NULL DEFAULT 'untitled',
+	`createdAt` datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+	PRIMARY KEY (`id`),
+	UNIQUE INDEX `Note_slug_key` (`slug`)
+) DEFAULT CHARACTER SET utf8mb4;
@@ -0,0 +1,2 @@
+ALTER TYPE "UserRole" ADD VALUE IF NOT EXISTS 'guest' BEFORE 'user';
+ALTER TYPE "UserRole" ADD VALUE IF NOT EXISTS 'editor' AFTER 'moderator';
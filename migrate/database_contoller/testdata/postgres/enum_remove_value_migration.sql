@@ -0,0 +1,5 @@
+-- Warning: Values moderator will be removed from enum "UserRole". Migration will fail if they are still used
+ALTER TYPE "UserRole" RENAME TO "UserRole_old";
+CREATE TYPE "UserRole" AS ENUM ('user','admin');
+ALTER TABLE "User" ALTER COLUMN "role" TYPE "UserRole" USING ("role"::text::"UserRole");
+DROP TYPE "UserRole_old";
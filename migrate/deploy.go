@@ -0,0 +1,39 @@
+package migrate
+
+import (
+	"GoRelCli/migrate/migration_file"
+	"GoRelCli/models/flag_model"
+	"GoRelCli/models/schema_model"
+	"errors"
+)
+
+// Deploy applies all pending migration files in order. It never generates new migrations.
+func Deploy(flags flag_model.Flags) error {
+	if !checkFlags(flags) {
+		return errors.New("path flag should be provided")
+	}
+
+	var goRelSchema schema_model.GoRelSchema
+
+	if err := loadConnection(flags.Path, &goRelSchema); err != nil {
+		return err
+	}
+
+	migrationsFolder, err := migration_file.GetMigrationsFolder(flags.Path)
+	if err != nil {
+		return err
+	}
+
+	databaseController, err := connect(goRelSchema, flags)
+	if err != nil {
+		return err
+	}
+	defer databaseController.Close()
+
+	if flags.DryRun {
+		_, err := printPendingMigrations(databaseController, migrationsFolder)
+		return err
+	}
+
+	return applyPendingMigrations(databaseController, migrationsFolder)
+}
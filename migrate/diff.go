@@ -0,0 +1,86 @@
+package migrate
+
+import (
+	"GoRelCli/migrate/database_contoller"
+	"GoRelCli/models/flag_model"
+	"GoRelCli/models/schema_model"
+	"GoRelCli/utils/logger"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func writeSqlScript(script string, output string) error {
+	if output == "" {
+		fmt.Print(script)
+		return nil
+	}
+
+	absolutePath, err := filepath.Abs(output)
+	if err != nil {
+		return err
+	}
+
+	return logger.LogStep("write sql to file", func() error {
+		return os.WriteFile(absolutePath, []byte(script), 0666)
+	})
+}
+
+// Diff prints sql required to bring the database to the state described in schema without running it.
+// With --from-empty flag sql is generated for an empty database and connection is not used.
+func Diff(flags flag_model.Flags) error {
+	if !checkFlags(flags) {
+		return errors.New("path flag should be provided")
+	}
+
+	if flags.Output == "" {
+		logger.Output = os.Stderr
+	}
+
+	var goRelSchema schema_model.GoRelSchema
+
+	enumNames, modelNames, err := loadSchema(flags.Path, &goRelSchema)
+	if err != nil {
+		return err
+	}
+
+	var steps []database_contoller.MigrationStep
+
+	if flags.FromEmpty {
+		if err := logger.LogStep("generate sql", func() error {
+			databaseController, err := database_contoller.NewOfflineDatabaseController(goRelSchema.Connection.Provider)
+			if err != nil {
+				return err
+			}
+			stepsInner, err := database_contoller.GenerateInitialMigration(databaseController, &goRelSchema, enumNames, modelNames)
+			if err != nil {
+				return err
+			}
+			steps = stepsInner
+			return nil
+		}); err != nil {
+			return err
+		}
+		return writeSqlScript(database_contoller.GenerateSqlScript(steps), flags.Output)
+	}
+
+	databaseController, err := connect(goRelSchema, flags)
+	if err != nil {
+		return err
+	}
+	defer databaseController.Close()
+
+	if err := logger.LogStep("generate sql", func() error {
+		stepsInner, _, err := database_contoller.GenerateMigration(databaseController, &goRelSchema, enumNames, modelNames)
+		if err != nil {
+			return err
+		}
+		steps = stepsInner
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return writeSqlScript(database_contoller.GenerateSqlScript(steps), flags.Output)
+}
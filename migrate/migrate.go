@@ -2,92 +2,236 @@ package migrate
 
 import (
 	"GoRelCli/migrate/database_contoller"
+	"GoRelCli/migrate/migration_file"
+	"GoRelCli/models/flag_model"
 	"GoRelCli/models/schema_model"
 	"GoRelCli/utils/logger"
 	"GoRelCli/utils/schema_parser"
+	"GoRelCli/utils/terminal"
 	"GoRelCli/utils/validator"
-	"bufio"
 	"errors"
 	"fmt"
-	"os"
-	"strings"
+	"time"
 )
 
-func checkFlags(args ...string) (valid bool) {
-	if args[0] == "" {
+func checkFlags(flags flag_model.Flags) (valid bool) {
+	if flags.Path == "" {
 		return false
 	}
 	return true
 }
 
-func requestPermissionToOverrideSchema() error {
-	fmt.Println("This action will delete all existing enums and tables. Are you sure you want to proceed? (Y-yes/N-no):")
-	reader := bufio.NewReader(os.Stdin)
-	str, err := reader.ReadString('\n')
-	if err != nil {
-		return err
+func loadSchema(path string, goRelSchema *schema_model.GoRelSchema) (enumNames []string, modelNames []string, err error) {
+	if err := logger.LogStep("load schema", func() error {
+		if err := schema_parser.LoadYmlSchema(path, goRelSchema); err != nil {
+			return err
+		}
+		return nil
+	}); err != nil {
+		return nil, nil, err
 	}
-	switch strings.ToLower(str[0:1]) {
-	case "y":
+
+	if err := logger.LogStep("validate schema", func() error {
+		enumNamesInn, modelNamesInn, err := validator.ValidateSchema(goRelSchema)
+		if err != nil {
+			return err
+		}
+		enumNames = enumNamesInn
+		modelNames = modelNamesInn
 		return nil
-	case "n":
-		return errors.New("user refused to give permission to override tables and enums")
-	default:
-		return errors.New("unknown option")
+	}); err != nil {
+		return nil, nil, err
 	}
+
+	return enumNames, modelNames, nil
+}
+
+// loadConnection loads schema without validating models, because only connection info is needed
+func loadConnection(path string, goRelSchema *schema_model.GoRelSchema) error {
+	return logger.LogStep("load schema", func() error {
+		if err := schema_parser.LoadYmlSchema(path, goRelSchema); err != nil {
+			return err
+		}
+		return nil
+	})
 }
 
-func Migrate(args ...string) error {
-	if err := requestPermissionToOverrideSchema(); err != nil {
-		return errors.New(fmt.Sprintf("error while requesting permission to override:\n\t%s", err))
+func connect(goRelSchema schema_model.GoRelSchema, flags flag_model.Flags) (database_contoller.DatabaseControllerInterface, error) {
+	if err := logger.LogStep("resolve connection url", func() error {
+		return schema_parser.ResolveConnectionUrl(&goRelSchema, flags.EnvFile)
+	}); err != nil {
+		return nil, err
 	}
-	if !checkFlags(args...) {
+
+	var databaseController database_contoller.DatabaseControllerInterface
+
+	if err := logger.LogStep("connect to db", func() error {
+		databaseControllerInner, err := database_contoller.NewDatabaseController(goRelSchema.Connection)
+		if err != nil {
+			return err
+		}
+		databaseController = databaseControllerInner
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return databaseController, nil
+}
+
+func getFinishedMigrationNames(appliedMigrations []database_contoller.AppliedMigration) []string {
+	var names []string
+	for _, migration := range appliedMigrations {
+		if migration.IsFinished() {
+			names = append(names, migration.Name)
+		}
+	}
+	return names
+}
+
+func applyPendingMigrations(databaseController database_contoller.DatabaseControllerInterface, migrationsFolder string) error {
+	return logger.LogStep("apply pending migrations", func() error {
+		migrations, err := migration_file.LoadMigrationFiles(migrationsFolder)
+		if err != nil {
+			return err
+		}
+
+		appliedMigrations, err := databaseController.GetAppliedMigrations()
+		if err != nil {
+			return err
+		}
+
+		pendingMigrations := migration_file.GetPendingMigrations(migrations, getFinishedMigrationNames(appliedMigrations))
+		if len(pendingMigrations) == 0 {
+			fmt.Println("No pending migrations found")
+			return nil
+		}
+
+		for _, migration := range pendingMigrations {
+			fmt.Println(fmt.Sprintf("Applying migration %s...", migration.Name))
+			if err := databaseController.ApplyMigration(migration.Name, migration.Checksum, migration.Script); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// printPendingMigrations prints pending migrations instead of applying them (used in dry run) and returns their count
+func printPendingMigrations(databaseController database_contoller.DatabaseControllerInterface, migrationsFolder string) (pendingCount int, err error) {
+	err = logger.LogStep("find pending migrations", func() error {
+		migrations, err := migration_file.LoadMigrationFiles(migrationsFolder)
+		if err != nil {
+			return err
+		}
+
+		appliedMigrations, err := databaseController.GetAppliedMigrations()
+		if err != nil {
+			return err
+		}
+
+		pendingMigrations := migration_file.GetPendingMigrations(migrations, getFinishedMigrationNames(appliedMigrations))
+		if len(pendingMigrations) == 0 {
+			fmt.Println("No pending migrations found")
+			return nil
+		}
+
+		for _, migration := range pendingMigrations {
+			fmt.Println(fmt.Sprintf("Pending migration %s (not applied in dry run):\n%s", migration.Name, migration.Script))
+		}
+		pendingCount = len(pendingMigrations)
+		return nil
+	})
+	return pendingCount, err
+}
+
+// Dev applies pending migrations, generates new migration file from the difference between database and schema and applies it
+func Dev(flags flag_model.Flags) error {
+	if !checkFlags(flags) {
 		return errors.New("path flag should be provided")
 	}
 
-	path := args[0]
 	var goRelSchema schema_model.GoRelSchema
 
-	if err := logger.LogStep("load schema", func() error {
-		if err := schema_parser.LoadYmlSchema(path, &goRelSchema); err != nil {
+	enumNames, modelNames, err := loadSchema(flags.Path, &goRelSchema)
+	if err != nil {
+		return err
+	}
+
+	migrationsFolder, err := migration_file.GetMigrationsFolder(flags.Path)
+	if err != nil {
+		return err
+	}
+
+	databaseController, err := connect(goRelSchema, flags)
+	if err != nil {
+		return err
+	}
+	defer databaseController.Close()
+
+	if flags.DryRun {
+		pendingCount, err := printPendingMigrations(databaseController, migrationsFolder)
+		if err != nil {
 			return err
 		}
-		return nil
-	}); err != nil {
+		// new migration is generated from the database, so it would repeat changes of pending migrations that are not applied
+		if pendingCount != 0 {
+			fmt.Println("Dry run: pending migrations should be applied before a new migration can be generated")
+			return nil
+		}
+	} else if err := applyPendingMigrations(databaseController, migrationsFolder); err != nil {
 		return err
 	}
 
-	var modelNames []string
-	var enumNames []string
+	var steps, downSteps []database_contoller.MigrationStep
 
-	if err := logger.LogStep("validate schema", func() error {
-		enumNamesInn, modelNamesInn, err := validator.ValidateSchema(&goRelSchema)
+	if err := logger.LogStep("generate migration", func() error {
+		stepsInner, downStepsInner, err := database_contoller.GenerateMigration(databaseController, &goRelSchema, enumNames, modelNames)
 		if err != nil {
 			return err
 		}
-		enumNames = enumNamesInn
-		modelNames = modelNamesInn
+		steps, downSteps = stepsInner, downStepsInner
 		return nil
 	}); err != nil {
 		return err
 	}
 
-	var databaseController database_contoller.DatabaseControllerInterface
+	if len(steps) == 0 {
+		fmt.Println("Database is already in sync with the schema. Nothing to migrate.")
+		return nil
+	}
 
-	if err := logger.LogStep("connect to db", func() error {
-		databaseControllerInner, err := database_contoller.NewDatabaseController(goRelSchema.Connection)
+	script := database_contoller.GenerateSqlScript(steps)
+	fmt.Println(fmt.Sprintf("Generated migration:\n%s", script))
+
+	if flags.DryRun {
+		fmt.Println("Dry run: migration is not saved and not applied")
+		return nil
+	}
+
+	if warnings := database_contoller.GetWarnings(steps); len(warnings) != 0 {
+		if err := terminal.RequestPermission(flags.Yes, "Migration contains potentially destructive changes:", warnings); err != nil {
+			return errors.New(fmt.Sprintf("error while requesting permission to apply migration:\n\t%s", err))
+		}
+	}
+
+	var migration migration_file.MigrationFile
+
+	if err := logger.LogStep("write migration file", func() error {
+		migrationInner, err := migration_file.WriteMigrationFile(migrationsFolder, migration_file.CreateMigrationName(flags.Name, time.Now()), script, database_contoller.GenerateSqlScript(downSteps))
 		if err != nil {
 			return err
 		}
-		databaseController = databaseControllerInner
+		migration = migrationInner
+		fmt.Println(fmt.Sprintf("Migration saved to %s", migration.Path))
 		return nil
 	}); err != nil {
 		return err
 	}
 
 	if err := logger.LogStep("run migrations", func() error {
-		err := databaseController.RunMigrations(&goRelSchema, enumNames, modelNames)
-		return err
+		return databaseController.ApplyMigration(migration.Name, migration.Checksum, migration.Script)
 	}); err != nil {
 		return err
 	}
@@ -0,0 +1,164 @@
+package migration_file
+
+import (
+	"GoRelCli/models/error_model/migration_error"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	migrationsFolderName = "migrations"
+	migrationFileName    = "migration.sql"
+	downFileName         = "down.sql"
+	timestampLayout      = "20060102150405"
+	defaultMigrationName = "migration"
+)
+
+var nameCleanupRegexp = regexp.MustCompile("[^a-z0-9]+")
+
+type MigrationFile struct {
+	Name       string
+	Path       string
+	Script     string
+	Checksum   string
+	DownScript string
+}
+
+// GetChecksum returns sha256 checksum of the migration script
+func GetChecksum(script string) string {
+	hash := sha256.Sum256([]byte(script))
+	return hex.EncodeToString(hash[:])
+}
+
+// GetMigrationsFolder returns path to the folder with migrations, which is located next to the schema file
+func GetMigrationsFolder(schemaPath string) (string, error) {
+	absolutePath, err := filepath.Abs(schemaPath)
+	if err != nil {
+		return "", migration_error.MigrationError{
+			Type: migration_error.ReadingMigrationError,
+			Text: fmt.Sprintf("Can't resolve path \"%s\"", schemaPath),
+		}
+	}
+	return filepath.Join(filepath.Dir(absolutePath), migrationsFolderName), nil
+}
+
+// LoadMigrationFiles reads all migrations from the folder ordered by their names (and so by creation time)
+func LoadMigrationFiles(folder string) ([]MigrationFile, error) {
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, migration_error.MigrationError{
+			Type: migration_error.ReadingMigrationError,
+			Text: fmt.Sprintf("Can't read migrations folder \"%s\": %s", folder, err),
+		}
+	}
+
+	var migrations []MigrationFile
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(folder, entry.Name(), migrationFileName)
+		script, err := os.ReadFile(path)
+		if err != nil {
+			return nil, migration_error.MigrationError{
+				Type: migration_error.ReadingMigrationError,
+				Text: fmt.Sprintf("Can't read migration file \"%s\": %s", path, err),
+			}
+		}
+
+		downPath := filepath.Join(folder, entry.Name(), downFileName)
+		downScript, err := os.ReadFile(downPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, migration_error.MigrationError{
+				Type: migration_error.ReadingMigrationError,
+				Text: fmt.Sprintf("Can't read down migration file \"%s\": %s", downPath, err),
+			}
+		}
+
+		migrations = append(migrations, MigrationFile{
+			Name:       entry.Name(),
+			Path:       path,
+			Script:     string(script),
+			Checksum:   GetChecksum(string(script)),
+			DownScript: string(downScript),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Name < migrations[j].Name
+	})
+
+	return migrations, nil
+}
+
+// CreateMigrationName creates unique migration name from the timestamp and name provided by user
+func CreateMigrationName(name string, createdAt time.Time) string {
+	name = strings.Trim(nameCleanupRegexp.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		name = defaultMigrationName
+	}
+	return fmt.Sprintf("%s_%s", createdAt.UTC().Format(timestampLayout), name)
+}
+
+// WriteMigrationFile creates folder for the migration and writes the script and the script that reverts it
+func WriteMigrationFile(folder string, name string, script string, downScript string) (MigrationFile, error) {
+	migrationFolder := filepath.Join(folder, name)
+	if err := os.MkdirAll(migrationFolder, os.ModePerm); err != nil {
+		return MigrationFile{}, migration_error.MigrationError{
+			Type: migration_error.WritingMigrationError,
+			Text: fmt.Sprintf("Can't create migration folder \"%s\": %s", migrationFolder, err),
+		}
+	}
+
+	path := filepath.Join(migrationFolder, migrationFileName)
+	if err := os.WriteFile(path, []byte(script), 0666); err != nil {
+		return MigrationFile{}, migration_error.MigrationError{
+			Type: migration_error.WritingMigrationError,
+			Text: fmt.Sprintf("Can't write migration file \"%s\": %s", path, err),
+		}
+	}
+
+	downPath := filepath.Join(migrationFolder, downFileName)
+	if err := os.WriteFile(downPath, []byte(downScript), 0666); err != nil {
+		return MigrationFile{}, migration_error.MigrationError{
+			Type: migration_error.WritingMigrationError,
+			Text: fmt.Sprintf("Can't write down migration file \"%s\": %s", downPath, err),
+		}
+	}
+
+	return MigrationFile{
+		Name:       name,
+		Path:       path,
+		Script:     script,
+		Checksum:   GetChecksum(script),
+		DownScript: downScript,
+	}, nil
+}
+
+// GetPendingMigrations returns migrations that are not present in the list of applied migrations
+func GetPendingMigrations(migrations []MigrationFile, appliedMigrationNames []string) []MigrationFile {
+	applied := make(map[string]bool, len(appliedMigrationNames))
+	for _, name := range appliedMigrationNames {
+		applied[name] = true
+	}
+
+	var pending []MigrationFile
+	for _, migration := range migrations {
+		if !applied[migration.Name] {
+			pending = append(pending, migration)
+		}
+	}
+	return pending
+}
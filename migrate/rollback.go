@@ -0,0 +1,110 @@
+package migrate
+
+import (
+	"GoRelCli/migrate/database_contoller"
+	"GoRelCli/migrate/migration_file"
+	"GoRelCli/models/flag_model"
+	"GoRelCli/models/schema_model"
+	"GoRelCli/utils/logger"
+	"GoRelCli/utils/terminal"
+	"errors"
+	"fmt"
+)
+
+// getMigrationsToRollback returns last applied migrations in the order they should be reverted
+func getMigrationsToRollback(migrations []migration_file.MigrationFile, appliedMigrations []database_contoller.AppliedMigration, steps int) ([]migration_file.MigrationFile, error) {
+	migrationsByName := make(map[string]migration_file.MigrationFile, len(migrations))
+	for _, migration := range migrations {
+		migrationsByName[migration.Name] = migration
+	}
+
+	finishedMigrationNames := getFinishedMigrationNames(appliedMigrations)
+	if steps > len(finishedMigrationNames) {
+		return nil, errors.New(fmt.Sprintf("can't rollback %d migrations, only %d migrations are applied", steps, len(finishedMigrationNames)))
+	}
+
+	var result []migration_file.MigrationFile
+	for index := len(finishedMigrationNames) - 1; index >= len(finishedMigrationNames)-steps; index-- {
+		name := finishedMigrationNames[index]
+		migration, exists := migrationsByName[name]
+		if !exists {
+			return nil, errors.New(fmt.Sprintf("migration %s is applied, but missing locally", name))
+		}
+		if migration.DownScript == "" {
+			return nil, errors.New(fmt.Sprintf("migration %s does not have down script", name))
+		}
+		result = append(result, migration)
+	}
+
+	return result, nil
+}
+
+// Rollback reverts last N applied migrations using their down scripts
+func Rollback(flags flag_model.Flags) error {
+	if !checkFlags(flags) {
+		return errors.New("path flag should be provided")
+	}
+
+	if flags.Steps < 1 {
+		return errors.New("steps flag should be greater than 0")
+	}
+
+	var goRelSchema schema_model.GoRelSchema
+
+	if err := loadConnection(flags.Path, &goRelSchema); err != nil {
+		return err
+	}
+
+	migrationsFolder, err := migration_file.GetMigrationsFolder(flags.Path)
+	if err != nil {
+		return err
+	}
+
+	databaseController, err := connect(goRelSchema, flags)
+	if err != nil {
+		return err
+	}
+	defer databaseController.Close()
+
+	var migrationsToRollback []migration_file.MigrationFile
+
+	if err := logger.LogStep("find migrations to rollback", func() error {
+		migrations, err := migration_file.LoadMigrationFiles(migrationsFolder)
+		if err != nil {
+			return err
+		}
+
+		appliedMigrations, err := databaseController.GetAppliedMigrations()
+		if err != nil {
+			return err
+		}
+
+		migrationsToRollbackInner, err := getMigrationsToRollback(migrations, appliedMigrations, flags.Steps)
+		if err != nil {
+			return err
+		}
+		migrationsToRollback = migrationsToRollbackInner
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	var names []string
+	for _, migration := range migrationsToRollback {
+		names = append(names, migration.Name)
+	}
+
+	if err := terminal.RequestPermission(flags.Yes, "These migrations will be reverted. Data in created tables and columns will be lost:", names); err != nil {
+		return errors.New(fmt.Sprintf("error while requesting permission to rollback migrations:\n\t%s", err))
+	}
+
+	return logger.LogStep("rollback migrations", func() error {
+		for _, migration := range migrationsToRollback {
+			fmt.Println(fmt.Sprintf("Reverting migration %s...", migration.Name))
+			if err := databaseController.RollbackMigration(migration.Name, migration.DownScript); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,134 @@
+package migrate
+
+import (
+	"GoRelCli/migrate/database_contoller"
+	"GoRelCli/migrate/migration_file"
+	"GoRelCli/models/flag_model"
+	"GoRelCli/models/schema_model"
+	"GoRelCli/utils/logger"
+	"errors"
+	"fmt"
+)
+
+type migrationState string
+
+const (
+	Applied  migrationState = "applied"
+	Pending                 = "pending"
+	Modified                = "modified after apply"
+	Failed                  = "failed"
+	Missing                 = "applied, but missing locally"
+)
+
+type migrationStatus struct {
+	name      string
+	state     migrationState
+	appliedAt string
+}
+
+func getMigrationStatuses(migrations []migration_file.MigrationFile, appliedMigrations []database_contoller.AppliedMigration) []migrationStatus {
+	appliedByName := make(map[string]database_contoller.AppliedMigration, len(appliedMigrations))
+	for _, appliedMigration := range appliedMigrations {
+		appliedByName[appliedMigration.Name] = appliedMigration
+	}
+
+	var statuses []migrationStatus
+	fileNames := make(map[string]bool, len(migrations))
+
+	for _, migration := range migrations {
+		fileNames[migration.Name] = true
+		appliedMigration, exists := appliedByName[migration.Name]
+
+		switch {
+		case !exists:
+			statuses = append(statuses, migrationStatus{name: migration.Name, state: Pending})
+		case !appliedMigration.IsFinished():
+			statuses = append(statuses, migrationStatus{name: migration.Name, state: Failed})
+		case appliedMigration.Checksum != migration.Checksum:
+			statuses = append(statuses, migrationStatus{name: migration.Name, state: Modified, appliedAt: appliedMigration.FinishedAt.Time.String()})
+		default:
+			statuses = append(statuses, migrationStatus{name: migration.Name, state: Applied, appliedAt: appliedMigration.FinishedAt.Time.String()})
+		}
+	}
+
+	for _, appliedMigration := range appliedMigrations {
+		if !fileNames[appliedMigration.Name] {
+			statuses = append(statuses, migrationStatus{name: appliedMigration.Name, state: Missing, appliedAt: appliedMigration.FinishedAt.Time.String()})
+		}
+	}
+
+	return statuses
+}
+
+// Status prints state of every migration and returns error if database is behind migration files
+func Status(flags flag_model.Flags) error {
+	if !checkFlags(flags) {
+		return errors.New("path flag should be provided")
+	}
+
+	var goRelSchema schema_model.GoRelSchema
+
+	if err := loadConnection(flags.Path, &goRelSchema); err != nil {
+		return err
+	}
+
+	migrationsFolder, err := migration_file.GetMigrationsFolder(flags.Path)
+	if err != nil {
+		return err
+	}
+
+	databaseController, err := connect(goRelSchema, flags)
+	if err != nil {
+		return err
+	}
+	defer databaseController.Close()
+
+	var statuses []migrationStatus
+
+	if err := logger.LogStep("get migrations status", func() error {
+		migrations, err := migration_file.LoadMigrationFiles(migrationsFolder)
+		if err != nil {
+			return err
+		}
+
+		appliedMigrations, err := databaseController.GetAppliedMigrations()
+		if err != nil {
+			return err
+		}
+
+		statuses = getMigrationStatuses(migrations, appliedMigrations)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if len(statuses) == 0 {
+		fmt.Println("No migrations found")
+		return nil
+	}
+
+	pendingCount, failedCount := 0, 0
+	for _, status := range statuses {
+		if status.appliedAt != "" {
+			fmt.Println(fmt.Sprintf("\t%s - %s (%s)", status.name, status.state, status.appliedAt))
+		} else {
+			fmt.Println(fmt.Sprintf("\t%s - %s", status.name, status.state))
+		}
+
+		switch status.state {
+		case Pending:
+			pendingCount++
+		case Failed:
+			failedCount++
+		}
+	}
+
+	if pendingCount != 0 || failedCount != 0 {
+		err := errors.New(fmt.Sprintf("database is behind migration files: %d pending and %d failed migrations", pendingCount, failedCount))
+		fmt.Println(err)
+		return err
+	}
+
+	fmt.Println("Database is up to date")
+	return nil
+}
@@ -10,6 +10,7 @@ const (
 	CloseConnectionError                       = "close connection error"
 	ConnectionError                            = "connection error"
 	SqlGenerationError                         = "sql generation error"
+	IntrospectionError                         = "introspection error"
 )
 
 type DatabaseError struct {
@@ -8,6 +8,7 @@ const (
 	ReadingFromStdioError EnvLoaderErrorType = "reading from stdio error"
 	ResolvingPathError                       = "resolving path error"
 	ReadingEnvFileError                      = "reading env file from fs error"
+	NonInteractiveError                      = "non-interactive mode error"
 )
 
 type EnvLoaderError struct {
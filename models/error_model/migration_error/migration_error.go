@@ -0,0 +1,19 @@
+package migration_error
+
+import "fmt"
+
+type MigrationErrorType string
+
+const (
+	ReadingMigrationError MigrationErrorType = "reading migration error"
+	WritingMigrationError                    = "writing migration error"
+)
+
+type MigrationError struct {
+	Type MigrationErrorType
+	Text string
+}
+
+func (e MigrationError) Error() string {
+	return fmt.Sprintf("Error while using migration files:\n%s - %s", e.Type, e.Text)
+}
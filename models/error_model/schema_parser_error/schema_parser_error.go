@@ -9,6 +9,7 @@ const (
 	EmptyEnvVariableError           = "env variable does not exist error"
 	FileReadingError                = "file not exists error"
 	PathParsingError                = "path parsing error"
+	FileWritingError                = "file writing error"
 )
 
 type SchemaParserError struct {
@@ -5,9 +5,10 @@ import "fmt"
 type ValidationErrorPosition string
 
 const (
-	ModelValidationError    ValidationErrorPosition = "model validation error"
-	EnumValidationError                             = "enum validation error"
-	RelationValidationError                         = "relation validation error"
+	ModelValidationError     ValidationErrorPosition = "model validation error"
+	EnumValidationError                              = "enum validation error"
+	RelationValidationError                          = "relation validation error"
+	GeneratorValidationError                         = "generator validation error"
 )
 
 type ValidationError struct {
@@ -0,0 +1,13 @@
+package flag_model
+
+type Flags struct {
+	Path        string
+	ProjectPath string
+	Name        string
+	Steps       int
+	Yes         bool
+	EnvFile     string
+	DryRun      bool
+	Output      string
+	FromEmpty   bool
+}
@@ -10,4 +10,5 @@ type Provider string
 const (
 	PostgreSQL Provider = "postgresql"
 	MySQL               = "mysql"
+	SQLite              = "sqlite"
 )
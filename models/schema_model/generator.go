@@ -0,0 +1,151 @@
+package schema_model
+
+import (
+	"GoRelCli/utils/naming"
+	"fmt"
+)
+
+// Generator contains options of the generate command
+type Generator struct {
+	NullableStrategy NullableStrategy `yaml:"nullableStrategy,omitempty"`
+	// Initialisms makes field names and enum constants follow go conventions for initialisms (userId -> UserID).
+	// It is off by default, so names of code generated before the option was added don't change.
+	Initialisms bool       `yaml:"initialisms,omitempty"`
+	Tags        []Tag      `yaml:"tags,omitempty"`
+	Templates   []Template `yaml:"templates,omitempty"`
+}
+
+// Template is a user text/template file rendered by the generate command
+type Template struct {
+	// Path of the template file, relative paths are resolved from the folder of the schema
+	Path string `yaml:"path"`
+	// Output is a path of the generated file relative to the project folder, it is a template too (e.g. repository/{{ .Model.Name }}.go)
+	Output string        `yaml:"output"`
+	Scope  TemplateScope `yaml:"scope,omitempty"`
+}
+
+// TemplateScope defines how many files are generated from the template
+type TemplateScope string
+
+const (
+	// SchemaScope renders one file for the whole schema
+	SchemaScope TemplateScope = "schema"
+	// ModelScope renders file for every model
+	ModelScope = "model"
+	// EnumScope renders file for every enum
+	EnumScope = "enum"
+)
+
+var TemplateScopes = []TemplateScope{SchemaScope, ModelScope, EnumScope}
+
+// GetScope returns scope of the template, one file is rendered for the schema if it is not specified
+func (t Template) GetScope() TemplateScope {
+	if t.Scope == "" {
+		return SchemaScope
+	}
+	return t.Scope
+}
+
+// Tag is a struct tag added to fields of generated models besides gorel tag
+type Tag struct {
+	Name      string    `yaml:"name"`
+	Naming    TagNaming `yaml:"naming,omitempty"`
+	OmitEmpty OmitEmpty `yaml:"omitEmpty,omitempty"`
+}
+
+var TagNames = []string{"json", "db", "yaml", "validate"}
+
+// TagNaming defines how property name is converted to the value of the tag
+type TagNaming string
+
+const (
+	// Camel converts names to lower camel case (user_id -> userId)
+	Camel TagNaming = "camel"
+	// Snake converts names to snake case (userId -> user_id)
+	Snake = "snake"
+	// Original uses property names as they are written in the schema
+	Original = "original"
+)
+
+var TagNamings = []TagNaming{Camel, Snake, Original}
+
+// OmitEmpty defines fields that get omitempty option of the tag
+type OmitEmpty string
+
+const (
+	// Never doesn't add omitempty
+	Never OmitEmpty = "never"
+	// NullableFields adds omitempty to nullable properties and relations
+	NullableFields = "nullable"
+	// Always adds omitempty to every field
+	Always = "always"
+)
+
+var OmitEmptyRules = []OmitEmpty{Never, NullableFields, Always}
+
+// GetNaming returns naming of the tag, db tag uses column names and other tags use camel case if it is not specified
+func (t Tag) GetNaming() TagNaming {
+	if t.Naming != "" {
+		return t.Naming
+	}
+	if t.Name == "db" {
+		return Original
+	}
+	return Camel
+}
+
+// GetOmitEmpty returns omitempty rule of the tag, omitempty is not added if it is not specified
+func (t Tag) GetOmitEmpty() OmitEmpty {
+	if t.OmitEmpty == "" {
+		return Never
+	}
+	return t.OmitEmpty
+}
+
+// FieldName returns name of the struct field generated for the property
+func (g *Generator) FieldName(propertyName string) string {
+	if g.Initialisms {
+		return naming.GoName(propertyName)
+	}
+	return naming.UpperFirst(propertyName)
+}
+
+// EnumValueName returns name of the constant generated for the enum value without the enum prefix (super_user -> SuperUser)
+func (g *Generator) EnumValueName(value string) string {
+	if g.Initialisms {
+		return naming.GoName(value)
+	}
+	return naming.Pascal(value)
+}
+
+// NullableStrategy defines how nullable properties are represented in generated structs
+type NullableStrategy string
+
+const (
+	// Pointer represents nullable properties with pointers (*int64)
+	Pointer NullableStrategy = "pointer"
+	// SqlNull represents nullable properties with sql.Null* types (sql.NullInt64), types without sql.Null* counterpart use pointers
+	SqlNull = "sqlNull"
+	// Optional represents nullable properties with generic Optional[T] type generated to the models package
+	Optional = "optional"
+)
+
+var NullableStrategies = []NullableStrategy{Pointer, SqlNull, Optional}
+
+// GetNullableStrategy returns nullable strategy of the generator, pointers are used if it is not specified
+func (g *Generator) GetNullableStrategy() NullableStrategy {
+	if g.NullableStrategy == "" {
+		return Pointer
+	}
+	return g.NullableStrategy
+}
+
+// NullableGoType wraps go type of the nullable property according to the strategy
+func NullableGoType(goType string, strategy NullableStrategy) string {
+	switch strategy {
+	case Optional:
+		return fmt.Sprintf("Optional[%s]", goType)
+	default:
+		return fmt.Sprintf("*%s", goType)
+	}
+}
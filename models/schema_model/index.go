@@ -0,0 +1,85 @@
+package schema_model
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+// Index is a multi-column index or unique constraint of the model
+type Index struct {
+	// Name of the index, it is created from names of the model and the fields if it is not specified
+	Name   string       `yaml:"name,omitempty"`
+	Fields []IndexField `yaml:"fields,flow"`
+	Unique bool         `yaml:"unique,omitempty"`
+	// Using is an index method, methods other than btree are supported only by postgresql
+	Using IndexMethod `yaml:"using,omitempty"`
+	// Where is a predicate of the partial index written in sql of the provider (e.g. "deletedAt" IS NULL)
+	Where string `yaml:"where,omitempty"`
+}
+
+// IndexField is a property of the index. It is written either as a name (createdAt) or as a mapping with sort order ({name: createdAt, sort: desc}).
+type IndexField struct {
+	Name string    `yaml:"name"`
+	Sort SortOrder `yaml:"sort,omitempty"`
+}
+
+// SortOrder defines order of the index field
+type SortOrder string
+
+const (
+	Asc  SortOrder = "asc"
+	Desc           = "desc"
+)
+
+var SortOrders = []SortOrder{Asc, Desc}
+
+// IndexMethod defines structure of the index
+type IndexMethod string
+
+const (
+	BTree IndexMethod = "btree"
+	Hash              = "hash"
+	Gin               = "gin"
+	Gist              = "gist"
+)
+
+var IndexMethods = []IndexMethod{BTree, Hash, Gin, Gist}
+
+func (f *IndexField) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&f.Name)
+	}
+	type plainIndexField IndexField
+	return node.Decode((*plainIndexField)(f))
+}
+
+// MarshalYAML writes fields with default sort order as names
+func (f IndexField) MarshalYAML() (any, error) {
+	if f.Sort == "" || f.Sort == Asc {
+		return f.Name, nil
+	}
+	type plainIndexField IndexField
+	return plainIndexField(f), nil
+}
+
+// FieldNames returns names of the index fields in the order of the index
+func (i Index) FieldNames() []string {
+	names := make([]string, len(i.Fields))
+	for index, field := range i.Fields {
+		names[index] = field.Name
+	}
+	return names
+}
+
+// GetName returns name of the index. Default names follow naming of unique constraints of properties (User_email_key), other indexes end with _idx.
+func (i Index) GetName(modelName string) string {
+	if i.Name != "" {
+		return i.Name
+	}
+	suffix := "idx"
+	if i.Unique {
+		suffix = "key"
+	}
+	return fmt.Sprintf("%s_%s_%s", modelName, strings.Join(i.FieldNames(), "_"), suffix)
+}
@@ -3,22 +3,141 @@ package schema_model
 import (
 	"GoRelCli/models/error_model/validation_error"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 )
 
 type Model struct {
-	Name       string     `yaml:"name"`
+	Name string `yaml:"name"`
+	// PrimaryKey lists properties of composite primary key, it is used instead of id flags of the properties
+	PrimaryKey []string   `yaml:"primaryKey,omitempty,flow"`
 	Properties []Property `yaml:"properties,flow"`
+	// Indexes contains multi-column indexes and unique constraints of the model
+	Indexes []Index `yaml:"indexes,omitempty"`
+}
+
+// GetPrimaryKey returns names of primary key properties, properties with id flag are used if primaryKey is not specified
+func (m *Model) GetPrimaryKey() []string {
+	if len(m.PrimaryKey) != 0 {
+		return m.PrimaryKey
+	}
+	var names []string
+	for _, property := range m.Properties {
+		if property.Id {
+			names = append(names, property.Name)
+		}
+	}
+	return names
+}
+
+// IsPrimaryKey checks if property is a part of the primary key
+func (m *Model) IsPrimaryKey(propertyName string) bool {
+	return slices.Contains(m.GetPrimaryKey(), propertyName)
+}
+
+// FindRelationPairs returns properties of the related model that can be the other side of the relation property.
+// Sides are paired by relationName, so several relations between the same models (and self relations) can be defined.
+func (m *Model) FindRelationPairs(property Property, related Model) []Property {
+	var pairs []Property
+	for _, other := range related.Properties {
+		if other.GetBaseType() != m.Name || other.RelationName != property.RelationName {
+			continue
+		}
+		if related.Name == m.Name && other.Name == property.Name {
+			continue
+		}
+		pairs = append(pairs, other)
+	}
+	return pairs
+}
+
+// JoinTable describes table that stores implicit many-to-many relation
+type JoinTable struct {
+	Name string
+	// Column references primary key of the model, RelatedColumn references primary key of the related model
+	Column        string
+	RelatedColumn string
+}
+
+// GetJoinTable returns join table of the implicit many-to-many relation, where both sides are lists without relationField.
+// Sides are ordered by model name (and by property name for self relations), the first side is stored in column A and the second one in column B.
+// Join table is named _<relationName> or _<A>To<B> (e.g. _UserToVideo) if relation has no name.
+func (m *Model) GetJoinTable(property Property, related Model) (JoinTable, bool) {
+	if !strings.HasSuffix(property.Type, "[]") || property.RelationField != "" {
+		return JoinTable{}, false
+	}
+	pairs := m.FindRelationPairs(property, related)
+	if len(pairs) != 1 || !strings.HasSuffix(pairs[0].Type, "[]") || pairs[0].RelationField != "" {
+		return JoinTable{}, false
+	}
+
+	joinTable := JoinTable{Column: "A", RelatedColumn: "B"}
+	first, second := m.Name, related.Name
+	if m.Name > related.Name || (m.Name == related.Name && property.Name > pairs[0].Name) {
+		joinTable = JoinTable{Column: "B", RelatedColumn: "A"}
+		first, second = related.Name, m.Name
+	}
+	joinTable.Name = fmt.Sprintf("_%sTo%s", first, second)
+	if property.RelationName != "" {
+		joinTable.Name = fmt.Sprintf("_%s", property.RelationName)
+	}
+	return joinTable, true
 }
 
 type Property struct {
-	Name           string `yaml:"name"`
-	Type           string `yaml:"type"`
-	Default        string `yaml:"default,omitempty"`
-	Unique         bool   `yaml:"unique"`
-	Id             bool   `yaml:"id"`
+	Name    string `yaml:"name"`
+	Type    string `yaml:"type"`
+	Default string `yaml:"default,omitempty"`
+	Unique  bool   `yaml:"unique,omitempty"`
+	Id      bool   `yaml:"id,omitempty"`
+	// RelationName pairs both sides of the relation, it is required when models have more than one relation between them
+	RelationName   string `yaml:"relationName,omitempty"`
 	RelationField  string `yaml:"relationField,omitempty"`
 	ReferenceField string `yaml:"referenceField,omitempty"`
+	// OnDelete and OnUpdate are actions of the foreign key created by the relation, noAction is used by default
+	OnDelete ReferentialAction `yaml:"onDelete,omitempty"`
+	OnUpdate ReferentialAction `yaml:"onUpdate,omitempty"`
+}
+
+// ReferentialAction defines what happens with relation field when referenced record is deleted or its key is updated
+type ReferentialAction string
+
+const (
+	Cascade    ReferentialAction = "cascade"
+	Restrict                     = "restrict"
+	SetNull                      = "setNull"
+	SetDefault                   = "setDefault"
+	NoAction                     = "noAction"
+)
+
+var ReferentialActions = []ReferentialAction{Cascade, Restrict, SetNull, SetDefault, NoAction}
+
+var referentialActionSql = map[ReferentialAction]string{
+	Cascade:    "CASCADE",
+	Restrict:   "RESTRICT",
+	SetNull:    "SET NULL",
+	SetDefault: "SET DEFAULT",
+}
+
+// GetSql returns sql clause of the action, it is empty for noAction, because it is the default action of every database
+func (a ReferentialAction) GetSql() string {
+	return referentialActionSql[a]
+}
+
+// ParseReferentialAction converts sql clause of the action to the action, noAction is returned as empty action
+func ParseReferentialAction(sql string) ReferentialAction {
+	for action, actionSql := range referentialActionSql {
+		if actionSql == sql {
+			return action
+		}
+	}
+	return ""
+}
+
+// GetBaseType returns type of the property without array and nullable suffixes (Todo[] -> Todo)
+func (p *Property) GetBaseType() string {
+	return strings.TrimSuffix(strings.TrimSuffix(p.Type, "[]"), "?")
 }
 
 func (p *Property) GetPostgresType() (postgresType string, isValidPostgresType bool) {
@@ -30,13 +149,46 @@ func (p *Property) GetPostgresType() (postgresType string, isValidPostgresType b
 	return postgresType, true
 }
 
-func (p *Property) GetGoLangType() (goType string, isValidGoType bool) {
+// GetSqliteType returns sqlite column type of the property. Arrays are stored as json encoded TEXT.
+func (p *Property) GetSqliteType() (sqliteType string, isValidSqliteType bool) {
+	typed := PropertyType(p.Type)
+	sqliteType = sqliteTypes[typed]
+	if sqliteType == "" {
+		return p.Type, false
+	}
+	return sqliteType, true
+}
+
+// GetMySqlType returns mysql column type of the property. Arrays are stored as json.
+func (p *Property) GetMySqlType() (mySqlType string, isValidMySqlType bool) {
+	typed := PropertyType(p.Type)
+	mySqlType = mySqlTypes[typed]
+	if mySqlType == "" {
+		return p.Type, false
+	}
+	return mySqlType, true
+}
+
+// GetGoLangType returns go type of the property and imports required by it. Nullable properties are wrapped according to the strategy.
+func (p *Property) GetGoLangType(strategy NullableStrategy) (goType string, imports []string, isValidGoType bool) {
 	typed := PropertyType(p.Type)
 	goType = goTypes[typed]
 	if goType == "" {
-		return p.Type, false
+		return p.Type, nil, false
 	}
-	return goType, true
+
+	isNullable := strings.HasSuffix(p.Type, "?")
+	if isNullable && strategy == SqlNull {
+		return sqlNullGoTypes[typed], []string{"database/sql"}, true
+	}
+
+	if strings.Contains(goType, "time.") {
+		imports = append(imports, "time")
+	}
+	if isNullable {
+		goType = NullableGoType(goType, strategy)
+	}
+	return goType, imports, true
 }
 
 func (p *Property) ValidateDefaultValue() (value any, err *validation_error.ValidationError) {
@@ -114,39 +266,109 @@ const (
 	DateTimeNullable              = "dateTime?"
 )
 
+// propertyTypesOrder is the order in which property types are matched with column types, so scalar types are preferred over arrays
+var propertyTypesOrder = []PropertyType{Int, Boolean, Float, String, DateTime, IntNullable, BooleanNullable, FloatNullable, StringNullable, DateTimeNullable, IntArr, BooleanArr, FloatArr, StringArr, DateTimeArr}
+
+func findPropertyType(types map[PropertyType]string, columnType string, nullable bool) (propertyType PropertyType, isFound bool) {
+	for _, propertyType := range propertyTypesOrder {
+		databaseType := types[propertyType]
+		isRequired := strings.HasSuffix(databaseType, " NOT NULL")
+		if strings.TrimSuffix(databaseType, " NOT NULL") == columnType && isRequired != nullable {
+			return propertyType, true
+		}
+	}
+	return "", false
+}
+
+// PropertyTypeFromPostgresType returns property type of the postgres column (opposite of GetPostgresType)
+func PropertyTypeFromPostgresType(columnType string, nullable bool) (propertyType PropertyType, isFound bool) {
+	return findPropertyType(postgresTypes, columnType, nullable)
+}
+
+// PropertyTypeFromSqliteType returns property type of the sqlite column (opposite of GetSqliteType)
+func PropertyTypeFromSqliteType(columnType string, nullable bool) (propertyType PropertyType, isFound bool) {
+	return findPropertyType(sqliteTypes, columnType, nullable)
+}
+
+// PropertyTypeFromMySqlType returns property type of the mysql column (opposite of GetMySqlType)
+func PropertyTypeFromMySqlType(columnType string, nullable bool) (propertyType PropertyType, isFound bool) {
+	return findPropertyType(mySqlTypes, columnType, nullable)
+}
+
 var (
 	postgresTypes = map[PropertyType]string{
-		Int:              "int NOT NULL",
+		Int:              "integer NOT NULL",
 		Boolean:          "boolean NOT NULL",
 		Float:            "double precision NOT NULL",
 		String:           "text NOT NULL",
-		DateTime:         "timestamptz NOT NULL",
-		IntArr:           "int[]",
+		DateTime:         "timestamp with time zone NOT NULL",
+		IntArr:           "integer[]",
 		BooleanArr:       "boolean[]",
 		FloatArr:         "double precision[]",
 		StringArr:        "text[]",
-		DateTimeArr:      "timestamptz[]",
-		IntNullable:      "int",
+		DateTimeArr:      "timestamp with time zone[]",
+		IntNullable:      "integer",
 		BooleanNullable:  "boolean",
 		FloatNullable:    "double precision",
 		StringNullable:   "text",
-		DateTimeNullable: "timestamptz",
+		DateTimeNullable: "timestamp with time zone",
+	}
+	sqliteTypes = map[PropertyType]string{
+		Int:              "INTEGER NOT NULL",
+		Boolean:          "BOOLEAN NOT NULL",
+		Float:            "REAL NOT NULL",
+		String:           "TEXT NOT NULL",
+		DateTime:         "DATETIME NOT NULL",
+		IntArr:           "TEXT",
+		BooleanArr:       "TEXT",
+		FloatArr:         "TEXT",
+		StringArr:        "TEXT",
+		DateTimeArr:      "TEXT",
+		IntNullable:      "INTEGER",
+		BooleanNullable:  "BOOLEAN",
+		FloatNullable:    "REAL",
+		StringNullable:   "TEXT",
+		DateTimeNullable: "DATETIME",
+	}
+	mySqlTypes = map[PropertyType]string{
+		Int:              "int NOT NULL",
+		Boolean:          "tinyint(1) NOT NULL",
+		Float:            "double NOT NULL",
+		String:           "text NOT NULL",
+		DateTime:         "datetime(6) NOT NULL",
+		IntArr:           "json",
+		BooleanArr:       "json",
+		FloatArr:         "json",
+		StringArr:        "json",
+		DateTimeArr:      "json",
+		IntNullable:      "int",
+		BooleanNullable:  "tinyint(1)",
+		FloatNullable:    "double",
+		StringNullable:   "text",
+		DateTimeNullable: "datetime(6)",
 	}
 	goTypes = map[PropertyType]string{
 		Int:              "int64",
 		Boolean:          "bool",
 		Float:            "float64",
 		String:           "string",
-		DateTime:         "time",
-		IntArr:           "[]int",
-		BooleanArr:       "[]boolean",
-		FloatArr:         "[]float",
+		DateTime:         "time.Time",
+		IntArr:           "[]int64",
+		BooleanArr:       "[]bool",
+		FloatArr:         "[]float64",
 		StringArr:        "[]string",
-		DateTimeArr:      "[]time",
+		DateTimeArr:      "[]time.Time",
 		IntNullable:      "int64",
 		BooleanNullable:  "bool",
 		FloatNullable:    "float64",
 		StringNullable:   "string",
-		DateTimeNullable: "time",
+		DateTimeNullable: "time.Time",
+	}
+	sqlNullGoTypes = map[PropertyType]string{
+		IntNullable:      "sql.NullInt64",
+		BooleanNullable:  "sql.NullBool",
+		FloatNullable:    "sql.NullFloat64",
+		StringNullable:   "sql.NullString",
+		DateTimeNullable: "sql.NullTime",
 	}
 )
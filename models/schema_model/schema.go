@@ -4,4 +4,5 @@ type GoRelSchema struct {
 	Connection Connection `yaml:"connection,flow"`
 	Models     []Model    `yaml:"models,flow"`
 	Enums      []Enum     `yaml:"enums,flow"`
+	Generator  Generator  `yaml:"generator,omitempty"`
 }
@@ -0,0 +1,137 @@
+package pull
+
+import (
+	"GoRelCli/migrate/database_contoller"
+	"GoRelCli/models/flag_model"
+	"GoRelCli/models/schema_model"
+	"GoRelCli/utils/logger"
+	"GoRelCli/utils/schema_parser"
+	"GoRelCli/utils/terminal"
+	"GoRelCli/utils/validator"
+	"errors"
+	"fmt"
+)
+
+func checkFlags(flags flag_model.Flags) (valid bool) {
+	if flags.Path == "" {
+		return false
+	}
+	return true
+}
+
+func connect(goRelSchema schema_model.GoRelSchema, flags flag_model.Flags) (database_contoller.DatabaseControllerInterface, error) {
+	// url is resolved in the copy of the schema, so env("...") is kept in the written file
+	if err := logger.LogStep("resolve connection url", func() error {
+		return schema_parser.ResolveConnectionUrl(&goRelSchema, flags.EnvFile)
+	}); err != nil {
+		return nil, err
+	}
+
+	var databaseController database_contoller.DatabaseControllerInterface
+
+	if err := logger.LogStep("connect to db", func() error {
+		databaseControllerInner, err := database_contoller.NewDatabaseController(goRelSchema.Connection)
+		if err != nil {
+			return err
+		}
+		databaseController = databaseControllerInner
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return databaseController, nil
+}
+
+// Pull introspects database from connection block of the schema and replaces models and enums of the schema with found tables and enums.
+// Parts of the database that can't be described by the schema are printed as warnings.
+func Pull(flags flag_model.Flags) error {
+	if !checkFlags(flags) {
+		return errors.New("path flag should be provided")
+	}
+
+	var goRelSchema schema_model.GoRelSchema
+
+	if err := logger.LogStep("load schema", func() error {
+		if err := schema_parser.LoadYmlSchema(flags.Path, &goRelSchema); err != nil {
+			return err
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if len(goRelSchema.Models) != 0 || len(goRelSchema.Enums) != 0 {
+		if err := terminal.RequestPermission(flags.Yes, "Schema already contains models or enums, they will be overwritten:", []string{flags.Path}); err != nil {
+			return err
+		}
+	}
+
+	databaseController, err := connect(goRelSchema, flags)
+	if err != nil {
+		return err
+	}
+	defer databaseController.Close()
+
+	var warnings []string
+
+	if err := logger.LogStep("introspect database", func() error {
+		models, enums, warningsInner, err := database_contoller.PullSchema(databaseController)
+		if err != nil {
+			return err
+		}
+		goRelSchema.Models = models
+		goRelSchema.Enums = enums
+		warnings = warningsInner
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	var enumNames, modelNames []string
+
+	if err := logger.LogStep("validate schema", func() error {
+		enumNamesInn, modelNamesInn, err := validator.ValidateSchema(&goRelSchema)
+		if err != nil {
+			return err
+		}
+		enumNames = enumNamesInn
+		modelNames = modelNamesInn
+		return nil
+	}); err != nil {
+		warnings = append(warnings, fmt.Sprintf("Pulled schema is not valid and should be fixed manually: %s", err))
+	} else {
+		if err := logger.LogStep("compare schema with database", func() error {
+			steps, _, err := database_contoller.GenerateMigration(databaseController, &goRelSchema, enumNames, modelNames)
+			if err != nil {
+				return err
+			}
+			if len(steps) != 0 {
+				warnings = append(warnings, fmt.Sprintf("Database differs from pulled schema, next migration will run:\n%s", database_contoller.GenerateSqlScript(steps)))
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := logger.LogStep("write schema to fs", func() error {
+		if err := schema_parser.WriteYmlSchema(flags.Path, goRelSchema); err != nil {
+			return err
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if len(warnings) != 0 {
+		fmt.Println("Warnings:")
+		for _, warning := range warnings {
+			fmt.Println(fmt.Sprintf("\t- %s", warning))
+		}
+	}
+
+	fmt.Println(fmt.Sprintf("Pulled %d models and %d enums to %s", len(goRelSchema.Models), len(goRelSchema.Enums), flags.Path))
+
+	return nil
+}
@@ -2,32 +2,60 @@ package env_loader
 
 import (
 	"GoRelCli/models/error_model/env_loader_error"
+	"GoRelCli/utils/terminal"
 	"bufio"
 	"fmt"
 	"github.com/joho/godotenv"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
-func LoadEnvFile() error {
+// EnvFileVariableName is the name of env variable that can be used instead of --env-file flag
+const EnvFileVariableName = "GOREL_ENV_FILE"
+
+func requestEnvFilePath() (string, error) {
+	if !terminal.IsInteractive() {
+		return "", env_loader_error.EnvLoaderError{
+			Type: env_loader_error.NonInteractiveError,
+			Text: fmt.Sprintf("Path to .env file is not provided and stdin is not a terminal. Use --env-file flag or %s env variable", EnvFileVariableName),
+		}
+	}
+
 	fmt.Println("Specify path to .env file (relative path only):")
 	reader := bufio.NewReader(os.Stdin)
 	relativePath, err := reader.ReadString('\n')
 
 	if err != nil {
-		return env_loader_error.EnvLoaderError{
+		return "", env_loader_error.EnvLoaderError{
 			Type: env_loader_error.ReadingFromStdioError,
 			Text: "Error while reading from stdio",
 		}
 	}
 
-	relativePath = relativePath[0 : len(relativePath)-2]
-	absolutePath, err := filepath.Abs(relativePath)
+	return strings.TrimSpace(relativePath), nil
+}
+
+// LoadEnvFile loads variables from .env file. If path is empty, it is taken from GOREL_ENV_FILE variable or requested from user.
+func LoadEnvFile(path string) error {
+	if path == "" {
+		path = os.Getenv(EnvFileVariableName)
+	}
+
+	if path == "" {
+		requestedPath, err := requestEnvFilePath()
+		if err != nil {
+			return err
+		}
+		path = requestedPath
+	}
+
+	absolutePath, err := filepath.Abs(path)
 
 	if err != nil {
 		return env_loader_error.EnvLoaderError{
 			Type: env_loader_error.ResolvingPathError,
-			Text: fmt.Sprintf("Can't resolve path \"%s\"", relativePath),
+			Text: fmt.Sprintf("Can't resolve path \"%s\"", path),
 		}
 	}
 
@@ -2,16 +2,21 @@ package logger
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"time"
 )
 
+// Output is the writer, where step logs are written. It can be changed to os.Stderr to keep stdout clean for command results.
+var Output io.Writer = os.Stdout
+
 func LogStep(stepName string, function func() error) error {
-	fmt.Println(fmt.Sprintf("Starting task with name '%s'...", stepName))
+	fmt.Fprintln(Output, fmt.Sprintf("Starting task with name '%s'...", stepName))
 	startTime := time.Now()
 	if err := function(); err != nil {
-		fmt.Println(fmt.Sprintf("Task with name '%s' failed in %v ms with error:\n%s", stepName, time.Since(startTime).Milliseconds(), err))
+		fmt.Fprintln(Output, fmt.Sprintf("Task with name '%s' failed in %v ms with error:\n%s", stepName, time.Since(startTime).Milliseconds(), err))
 		return err
 	}
-	fmt.Println(fmt.Sprintf("Task with name '%s' successfully ended in %v ms", stepName, time.Since(startTime).Milliseconds()))
+	fmt.Fprintln(Output, fmt.Sprintf("Task with name '%s' successfully ended in %v ms", stepName, time.Since(startTime).Milliseconds()))
 	return nil
 }
@@ -0,0 +1,117 @@
+package naming
+
+import (
+	"strings"
+	"unicode"
+)
+
+// UpperFirst makes the first letter of the name uppercase keeping the rest of it (isVerified -> IsVerified)
+func UpperFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[0:1]) + name[1:]
+}
+
+// LowerFirst makes the first letter of the name lowercase keeping the rest of it (UserRole -> userRole)
+func LowerFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToLower(name[0:1]) + name[1:]
+}
+
+// Pluralize returns plural form of english noun (user -> users, category -> categories)
+func Pluralize(name string) string {
+	switch {
+	case strings.HasSuffix(name, "s") || strings.HasSuffix(name, "x") || strings.HasSuffix(name, "ch") || strings.HasSuffix(name, "sh"):
+		return name + "es"
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsAny(name[len(name)-2:len(name)-1], "aeiou"):
+		return name[0:len(name)-1] + "ies"
+	default:
+		return name + "s"
+	}
+}
+
+// commonInitialisms are written in upper case by go naming conventions (userId -> UserID)
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true, "GUID": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true, "LHS": true, "QPS": true,
+	"RAM": true, "RHS": true, "RPC": true, "SLA": true, "SMTP": true, "SQL": true, "SSH": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true, "URI": true, "URL": true,
+	"UTF8": true, "VM": true, "XML": true, "XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// Words splits the name into words by separators and case changes (userID -> user, ID; HTTPServer -> HTTP, Server; user_id -> user, id)
+func Words(name string) []string {
+	var words []string
+	var current []rune
+	runes := []rune(name)
+	for index, char := range runes {
+		if char == '_' || char == '-' || char == ' ' {
+			if len(current) != 0 {
+				words = append(words, string(current))
+				current = nil
+			}
+			continue
+		}
+
+		if len(current) != 0 && unicode.IsUpper(char) {
+			previous := runes[index-1]
+			nextIsLower := index+1 < len(runes) && unicode.IsLower(runes[index+1])
+			if !unicode.IsUpper(previous) || nextIsLower {
+				words = append(words, string(current))
+				current = nil
+			}
+		}
+		current = append(current, char)
+	}
+	if len(current) != 0 {
+		words = append(words, string(current))
+	}
+	return words
+}
+
+// GoName converts the name to exported go identifier with common initialisms in upper case (userId -> UserID, avatar_url -> AvatarURL)
+func GoName(name string) string {
+	var builder strings.Builder
+	for _, word := range Words(name) {
+		if upper := strings.ToUpper(word); commonInitialisms[upper] {
+			builder.WriteString(upper)
+			continue
+		}
+		builder.WriteString(UpperFirst(word))
+	}
+	return builder.String()
+}
+
+// Pascal converts the name to upper camel case keeping case of the rest of the words (super_user -> SuperUser, userId -> UserId)
+func Pascal(name string) string {
+	var builder strings.Builder
+	for _, word := range Words(name) {
+		builder.WriteString(UpperFirst(word))
+	}
+	return builder.String()
+}
+
+// Camel converts the name to lower camel case (user_id -> userId, UserID -> userId)
+func Camel(name string) string {
+	var builder strings.Builder
+	for index, word := range Words(name) {
+		word = strings.ToLower(word)
+		if index != 0 {
+			word = UpperFirst(word)
+		}
+		builder.WriteString(word)
+	}
+	return builder.String()
+}
+
+// Snake converts the name to snake case (isVerified -> is_verified, userID -> user_id)
+func Snake(name string) string {
+	words := Words(name)
+	for index, word := range words {
+		words[index] = strings.ToLower(word)
+	}
+	return strings.Join(words, "_")
+}
@@ -0,0 +1,29 @@
+package naming
+
+import "testing"
+
+func TestNames(t *testing.T) {
+	tests := []struct {
+		name   string
+		pascal string
+		goName string
+	}{
+		{name: "Admin", pascal: "Admin", goName: "Admin"},
+		{name: "super_user", pascal: "SuperUser", goName: "SuperUser"},
+		{name: "super-user", pascal: "SuperUser", goName: "SuperUser"},
+		{name: "superUser", pascal: "SuperUser", goName: "SuperUser"},
+		{name: "ADMIN", pascal: "ADMIN", goName: "ADMIN"},
+		{name: "userId", pascal: "UserId", goName: "UserID"},
+		{name: "api_user", pascal: "ApiUser", goName: "APIUser"},
+		{name: "_", pascal: "", goName: ""},
+	}
+
+	for _, test := range tests {
+		if pascal := Pascal(test.name); pascal != test.pascal {
+			t.Errorf("Pascal(%q) = %q, want %q", test.name, pascal, test.pascal)
+		}
+		if goName := GoName(test.name); goName != test.goName {
+			t.Errorf("GoName(%q) = %q, want %q", test.name, goName, test.goName)
+		}
+	}
+}
@@ -46,19 +46,34 @@ func parseYmlFileToSchema(file []byte) (schema_model.GoRelSchema, error) {
 	return goRelSchema, nil
 }
 
-func parseConnectionUrl(schema *schema_model.GoRelSchema) error {
+// addConnectTimeout adds connect_timeout parameter to postgres url. Other drivers don't support it.
+func addConnectTimeout(provider schema_model.Provider, url string) string {
+	if provider != schema_model.PostgreSQL || strings.Contains(url, "connect_timeout") {
+		return url
+	}
+	if strings.Contains(url, "?") {
+		return url + "&connect_timeout=5"
+	}
+	return url + "?connect_timeout=5"
+}
+
+// ResolveConnectionUrl replaces env("VARIABLE") function in connection url with the value of the variable.
+// .env file is loaded only if the variable does not exist in the environment.
+func ResolveConnectionUrl(schema *schema_model.GoRelSchema, envFilePath string) error {
 	isEnvFunc, err := regexp.MatchString("^env\\(\\\"\\S*\\\"\\)$", schema.Connection.Url)
 	if !isEnvFunc || err != nil {
-		if !strings.Contains(schema.Connection.Url, "connect_timeout") {
-			schema.Connection.Url += "&connect_timeout=5"
-		}
+		schema.Connection.Url = addConnectTimeout(schema.Connection.Provider, schema.Connection.Url)
+		return nil
 	}
 
-	if err := env_loader.LoadEnvFile(); err != nil {
-		return err
+	envVariableName := schema.Connection.Url[5 : len(schema.Connection.Url)-2]
+
+	if _, exists := os.LookupEnv(envVariableName); !exists {
+		if err := env_loader.LoadEnvFile(envFilePath); err != nil {
+			return err
+		}
 	}
 
-	envVariableName := schema.Connection.Url[5 : len(schema.Connection.Url)-2]
 	urlEnv, exists := os.LookupEnv(envVariableName)
 
 	if !exists {
@@ -68,11 +83,7 @@ func parseConnectionUrl(schema *schema_model.GoRelSchema) error {
 		}
 	}
 
-	if !strings.Contains(urlEnv, "connect_timeout") {
-		schema.Connection.Url = urlEnv + "&connect_timeout=5"
-	} else {
-		schema.Connection.Url = urlEnv
-	}
+	schema.Connection.Url = addConnectTimeout(schema.Connection.Provider, urlEnv)
 	return nil
 }
 
@@ -86,12 +97,62 @@ func LoadYmlSchema(path string, value *schema_model.GoRelSchema) error {
 	if err != nil {
 		return err
 	}
-	if err := parseConnectionUrl(value); err != nil {
-		return err
-	}
 	return nil
 }
 
+// setBlockStyle removes flow style from all nodes, so written schema is easy to read and edit
+func setBlockStyle(node *yaml.Node) {
+	node.Style &^= yaml.FlowStyle
+	for _, child := range node.Content {
+		setBlockStyle(child)
+	}
+}
+
+// WriteYmlSchema writes schema to the file, file is created if it doesn't exist
+func WriteYmlSchema(path string, schema schema_model.GoRelSchema) (err error) {
+	absolutePath, err := filepath.Abs(path)
+	if err != nil {
+		return schema_parser_error.SchemaParserError{
+			Type: schema_parser_error.PathParsingError,
+			Text: err.Error(),
+		}
+	}
+
+	var node yaml.Node
+	if err := node.Encode(schema); err != nil {
+		return schema_parser_error.SchemaParserError{
+			Type: schema_parser_error.ParsingError,
+			Text: err.Error(),
+		}
+	}
+	setBlockStyle(&node)
+
+	file, err := os.OpenFile(absolutePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+	if err != nil {
+		return schema_parser_error.SchemaParserError{
+			Type: schema_parser_error.FileWritingError,
+			Text: fmt.Sprintf("Error while writing schema file: %s", err),
+		}
+	}
+
+	defer func(file *os.File) {
+		if errInn := file.Close(); errInn != nil && err == nil {
+			err = errInn
+		}
+	}(file)
+
+	encoder := yaml.NewEncoder(file)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return schema_parser_error.SchemaParserError{
+			Type: schema_parser_error.FileWritingError,
+			Text: fmt.Sprintf("Error while writing schema file: %s", err),
+		}
+	}
+
+	return encoder.Close()
+}
+
 func IndexSchema(schema schema_model.GoRelSchema) (enumNames []string, modelNames []string) {
 	modelNames = make([]string, len(schema.Models))
 	enumNames = make([]string, len(schema.Enums))
@@ -0,0 +1,47 @@
+package terminal
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"golang.org/x/term"
+	"os"
+	"strings"
+)
+
+// IsInteractive checks if stdin is attached to a terminal, so user can answer prompts
+func IsInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// RequestPermission prints message with items and asks user to confirm it. yes gives permission without asking (--yes flag).
+func RequestPermission(yes bool, message string, items []string) error {
+	fmt.Println(message)
+	for _, item := range items {
+		fmt.Println(fmt.Sprintf("\t- %s", item))
+	}
+
+	if yes {
+		fmt.Println("Permission is given with --yes flag")
+		return nil
+	}
+
+	if !IsInteractive() {
+		return errors.New("stdin is not a terminal, so permission can't be requested. Use --yes flag to proceed in non-interactive mode")
+	}
+
+	fmt.Println("Are you sure you want to proceed? (Y-yes/N-no):")
+	reader := bufio.NewReader(os.Stdin)
+	str, err := reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(str[0:1]) {
+	case "y":
+		return nil
+	case "n":
+		return errors.New("user refused to give permission")
+	default:
+		return errors.New("unknown option")
+	}
+}
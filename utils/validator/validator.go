@@ -118,6 +118,21 @@ func CleanupNames(schema *schema_model.GoRelSchema) {
 			if checkNameForSpecialCharacter(property.ReferenceField) {
 				schema.Models[index].Properties[propertyIndex].ReferenceField = cleanupString(property.ReferenceField, nameMapper)
 			}
+			if checkNameForSpecialCharacter(property.RelationName) {
+				schema.Models[index].Properties[propertyIndex].RelationName = cleanupString(property.RelationName, nameMapper)
+			}
+		}
+		for keyIndex, name := range model.PrimaryKey {
+			if checkNameForSpecialCharacter(name) {
+				schema.Models[index].PrimaryKey[keyIndex] = cleanupString(name, nameMapper)
+			}
+		}
+		for indexIndex, modelIndex := range model.Indexes {
+			for fieldIndex, field := range modelIndex.Fields {
+				if checkNameForSpecialCharacter(field.Name) {
+					schema.Models[index].Indexes[indexIndex].Fields[fieldIndex].Name = cleanupString(field.Name, nameMapper)
+				}
+			}
 		}
 	}
 }
@@ -196,7 +211,7 @@ func validateType(property schema_model.Property, enumNames []string, modelNames
 		propertyType = strings.Replace(propertyType, "?", "", 1)
 	}
 
-	if isEnum := slices.Contains(enumNames, property.Type); isEnum {
+	if isEnum := slices.Contains(enumNames, strings.TrimSuffix(property.Type, "?")); isEnum {
 		return true
 	}
 
@@ -257,6 +272,25 @@ func validateEnums(schema schema_model.GoRelSchema) *validation_error.Validation
 			}
 		}
 
+		// values become go constants, names that differ only in separators (super_user, superUser) would collide
+		for valueIndex, value := range enum.Values {
+			name := schema.Generator.EnumValueName(value)
+			if name == "" {
+				return &validation_error.ValidationError{
+					Position: validation_error.EnumValidationError,
+					Text:     fmt.Sprintf("Value %s of enum with name %s has no letters", value, enum.Name),
+				}
+			}
+			for _, other := range enum.Values[:valueIndex] {
+				if schema.Generator.EnumValueName(other) == name {
+					return &validation_error.ValidationError{
+						Position: validation_error.EnumValidationError,
+						Text:     fmt.Sprintf("Values %s and %s of enum with name %s have the same go constant %s%s", other, value, enum.Name, enum.Name, name),
+					}
+				}
+			}
+		}
+
 	}
 
 	return nil
@@ -270,6 +304,7 @@ func validateModels(schema schema_model.GoRelSchema, enumNames []string, modelNa
 		}
 	}
 
+	var indexNames []string
 	for _, model := range schema.Models {
 		isNameEmpty := model.Name == ""
 		hasLessThanTwoProperties := len(model.Properties) < 2
@@ -313,39 +348,8 @@ func validateModels(schema schema_model.GoRelSchema, enumNames []string, modelNa
 
 			if property.Id {
 				idFieldCount++
-				isEnumType := slices.Contains(enumNames, property.Type)
-
-				if isEnumType {
-					return &validation_error.ValidationError{
-						Position: validation_error.ModelValidationError,
-						Text:     fmt.Sprintf("Model with name %s has id property (%s) with enum type", model.Name, property.Name),
-					}
-				}
-
-				typeAsPropertyType := schema_model.PropertyType(property.Type)
-				isOptionalType := typeAsPropertyType == schema_model.StringNullable || typeAsPropertyType == schema_model.IntNullable || typeAsPropertyType == schema_model.BooleanNullable || typeAsPropertyType == schema_model.FloatNullable || typeAsPropertyType == schema_model.DateTimeNullable
-				isArrayType := typeAsPropertyType == schema_model.StringArr || typeAsPropertyType == schema_model.IntArr || typeAsPropertyType == schema_model.BooleanArr || typeAsPropertyType == schema_model.FloatArr || typeAsPropertyType == schema_model.DateTimeArr
-
-				if isOptionalType {
-					return &validation_error.ValidationError{
-						Position: validation_error.ModelValidationError,
-						Text:     fmt.Sprintf("Model with name %s has id property (%s) with optional type", model.Name, property.Name),
-					}
-				}
-
-				if isArrayType {
-					return &validation_error.ValidationError{
-						Position: validation_error.ModelValidationError,
-						Text:     fmt.Sprintf("Model with name %s has id property (%s) with array type", model.Name, property.Name),
-					}
-				}
-			}
-
-			//TODO: Table can possibly have 2 id fields. Add support for that.
-			if idFieldCount > 1 {
-				return &validation_error.ValidationError{
-					Position: validation_error.ModelValidationError,
-					Text:     fmt.Sprintf("model with name %s has more than one id field", model.Name),
+				if err := validateIdProperty(model, property, enumNames); err != nil {
+					return err
 				}
 			}
 
@@ -363,56 +367,385 @@ func validateModels(schema schema_model.GoRelSchema, enumNames []string, modelNa
 			}
 		}
 
-		if idFieldCount == 0 {
+		if len(model.PrimaryKey) != 0 {
+			if err := validatePrimaryKey(model, idFieldCount, enumNames, modelNames); err != nil {
+				return err
+			}
+		} else if idFieldCount == 0 {
 			return &validation_error.ValidationError{
 				Position: validation_error.ModelValidationError,
 				Text:     fmt.Sprintf("model with name %s does not have id field", model.Name),
 			}
 		}
+
+		for _, index := range model.Indexes {
+			if err := validateIndex(model, index, schema.Connection.Provider, modelNames); err != nil {
+				return err
+			}
+			// indexes share one namespace in postgresql and sqlite
+			name := index.GetName(model.Name)
+			if slices.Contains(indexNames, name) {
+				return &validation_error.ValidationError{
+					Position: validation_error.ModelValidationError,
+					Text:     fmt.Sprintf("index name %s is used more than once, set name of the index", name),
+				}
+			}
+			indexNames = append(indexNames, name)
+		}
 	}
 
 	return nil
 }
 
-func referenceFieldExists(schema schema_model.GoRelSchema, referenceModelName string, relationModelName string) bool {
-	modelIndex := -1
+// maxIndexNameLength is the length of identifiers in postgresql, longer names are truncated by the database
+const maxIndexNameLength = 63
 
-	for index, model := range schema.Models {
-		if model.Name == referenceModelName {
-			modelIndex = index
-			break
+// validateIndex checks fields and options of the index, options that are not supported by the provider are rejected
+func validateIndex(model schema_model.Model, index schema_model.Index, provider schema_model.Provider, modelNames []string) *validation_error.ValidationError {
+	name := index.GetName(model.Name)
+	if len(index.Fields) == 0 {
+		return &validation_error.ValidationError{
+			Position: validation_error.ModelValidationError,
+			Text:     fmt.Sprintf("index %s of model with name %s has no fields", name, model.Name),
 		}
 	}
 
-	if modelIndex == -1 {
-		return false
+	for fieldIndex, field := range index.Fields {
+		if slices.ContainsFunc(index.Fields[:fieldIndex], func(other schema_model.IndexField) bool { return other.Name == field.Name }) {
+			return &validation_error.ValidationError{
+				Position: validation_error.ModelValidationError,
+				Text:     fmt.Sprintf("index %s of model with name %s contains field %s more than once", name, model.Name, field.Name),
+			}
+		}
+
+		propertyIndex := slices.IndexFunc(model.Properties, func(property schema_model.Property) bool {
+			return property.Name == field.Name
+		})
+		if propertyIndex == -1 {
+			return &validation_error.ValidationError{
+				Position: validation_error.ModelValidationError,
+				Text:     fmt.Sprintf("index %s of model with name %s contains field %s, which does not exist", name, model.Name, field.Name),
+			}
+		}
+		property := model.Properties[propertyIndex]
+		if slices.Contains(modelNames, strings.TrimSuffix(strings.TrimSuffix(property.Type, "[]"), "?")) {
+			return &validation_error.ValidationError{
+				Position: validation_error.ModelValidationError,
+				Text:     fmt.Sprintf("index %s of model with name %s contains relation property %s, use its relationField instead", name, model.Name, field.Name),
+			}
+		}
+
+		if field.Sort != "" && !slices.Contains(schema_model.SortOrders, field.Sort) {
+			return &validation_error.ValidationError{
+				Position: validation_error.ModelValidationError,
+				Text:     fmt.Sprintf("unknown sort %s of field %s in index %s, use one of: asc, desc", field.Sort, field.Name, name),
+			}
+		}
+	}
+
+	if len(name) > maxIndexNameLength {
+		return &validation_error.ValidationError{
+			Position: validation_error.ModelValidationError,
+			Text:     fmt.Sprintf("index name %s is longer than %d characters, set shorter name of the index", name, maxIndexNameLength),
+		}
+	}
+
+	if index.Using != "" && !slices.Contains(schema_model.IndexMethods, index.Using) {
+		return &validation_error.ValidationError{
+			Position: validation_error.ModelValidationError,
+			Text:     fmt.Sprintf("unknown method %s of index %s, use one of: btree, hash, gin, gist", index.Using, name),
+		}
+	}
+	if index.Using != "" && index.Using != schema_model.BTree && provider != schema_model.PostgreSQL {
+		return &validation_error.ValidationError{
+			Position: validation_error.ModelValidationError,
+			Text:     fmt.Sprintf("method %s of index %s is supported only by postgresql", index.Using, name),
+		}
+	}
+	if index.Where != "" && provider == schema_model.MySQL {
+		return &validation_error.ValidationError{
+			Position: validation_error.ModelValidationError,
+			Text:     fmt.Sprintf("index %s has where predicate, partial indexes are not supported by mysql", name),
+		}
 	}
+	return nil
+}
 
-	model := schema.Models[modelIndex]
+// validateIdProperty checks that property can be a part of the primary key
+func validateIdProperty(model schema_model.Model, property schema_model.Property, enumNames []string) *validation_error.ValidationError {
+	isEnumType := slices.Contains(enumNames, property.Type)
 
-	for _, property := range model.Properties {
-		if property.Type == relationModelName || property.Type == fmt.Sprintf("%s[]", relationModelName) {
-			return true
+	if isEnumType {
+		return &validation_error.ValidationError{
+			Position: validation_error.ModelValidationError,
+			Text:     fmt.Sprintf("Model with name %s has id property (%s) with enum type", model.Name, property.Name),
 		}
 	}
 
-	return false
+	typeAsPropertyType := schema_model.PropertyType(property.Type)
+	isOptionalType := typeAsPropertyType == schema_model.StringNullable || typeAsPropertyType == schema_model.IntNullable || typeAsPropertyType == schema_model.BooleanNullable || typeAsPropertyType == schema_model.FloatNullable || typeAsPropertyType == schema_model.DateTimeNullable
+	isArrayType := typeAsPropertyType == schema_model.StringArr || typeAsPropertyType == schema_model.IntArr || typeAsPropertyType == schema_model.BooleanArr || typeAsPropertyType == schema_model.FloatArr || typeAsPropertyType == schema_model.DateTimeArr
+
+	if isOptionalType {
+		return &validation_error.ValidationError{
+			Position: validation_error.ModelValidationError,
+			Text:     fmt.Sprintf("Model with name %s has id property (%s) with optional type", model.Name, property.Name),
+		}
+	}
+
+	if isArrayType {
+		return &validation_error.ValidationError{
+			Position: validation_error.ModelValidationError,
+			Text:     fmt.Sprintf("Model with name %s has id property (%s) with array type", model.Name, property.Name),
+		}
+	}
+	return nil
+}
+
+// validatePrimaryKey checks model-level primary key, it can't be combined with id flags of the properties
+func validatePrimaryKey(model schema_model.Model, idFieldCount int, enumNames []string, modelNames []string) *validation_error.ValidationError {
+	if idFieldCount != 0 {
+		return &validation_error.ValidationError{
+			Position: validation_error.ModelValidationError,
+			Text:     fmt.Sprintf("model with name %s has both primaryKey and id fields, use only one of them", model.Name),
+		}
+	}
+
+	for index, name := range model.PrimaryKey {
+		if slices.Contains(model.PrimaryKey[:index], name) {
+			return &validation_error.ValidationError{
+				Position: validation_error.ModelValidationError,
+				Text:     fmt.Sprintf("primaryKey of model with name %s contains property %s more than once", model.Name, name),
+			}
+		}
+
+		propertyIndex := slices.IndexFunc(model.Properties, func(property schema_model.Property) bool {
+			return property.Name == name
+		})
+		if propertyIndex == -1 {
+			return &validation_error.ValidationError{
+				Position: validation_error.ModelValidationError,
+				Text:     fmt.Sprintf("primaryKey of model with name %s contains property %s, which does not exist", model.Name, name),
+			}
+		}
+
+		property := model.Properties[propertyIndex]
+		if slices.Contains(modelNames, strings.TrimSuffix(strings.TrimSuffix(property.Type, "[]"), "?")) {
+			return &validation_error.ValidationError{
+				Position: validation_error.ModelValidationError,
+				Text:     fmt.Sprintf("primaryKey of model with name %s contains relation property %s, use its relationField instead", model.Name, name),
+			}
+		}
+		if err := validateIdProperty(model, property, enumNames); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func validateRelations(schema schema_model.GoRelSchema) *validation_error.ValidationError {
+func findModel(schema schema_model.GoRelSchema, name string) (schema_model.Model, bool) {
+	for _, model := range schema.Models {
+		if model.Name == name {
+			return model, true
+		}
+	}
+	return schema_model.Model{}, false
+}
+
+func validateRelations(schema schema_model.GoRelSchema, modelNames []string) *validation_error.ValidationError {
 	for _, model := range schema.Models {
 		for _, property := range model.Properties {
-			if property.ReferenceField != "" && property.RelationField != "" && !referenceFieldExists(schema, property.Type, model.Name) {
-				return &validation_error.ValidationError{
-					Position: validation_error.RelationValidationError,
-					Text:     fmt.Sprintf("relations should be created for both models %s and %s", property.Type, model.Name),
+			related, isRelation := findModel(schema, property.GetBaseType())
+			if !isRelation {
+				if property.RelationName != "" {
+					return &validation_error.ValidationError{
+						Position: validation_error.RelationValidationError,
+						Text:     fmt.Sprintf("relationName of property %s in model %s can be set only on relation properties", property.Name, model.Name),
+					}
 				}
+			} else if err := validateRelationPair(model, property, related, modelNames); err != nil {
+				return err
+			}
+			if err := validateReferentialAction(model, property, "onDelete", property.OnDelete, schema.Connection.Provider); err != nil {
+				return err
+			}
+			if err := validateReferentialAction(model, property, "onUpdate", property.OnUpdate, schema.Connection.Provider); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateRelationPair checks that the relation property has exactly one property on the other side of the relation
+func validateRelationPair(model schema_model.Model, property schema_model.Property, related schema_model.Model, modelNames []string) *validation_error.ValidationError {
+	pairs := model.FindRelationPairs(property, related)
+	if len(pairs) == 0 {
+		text := fmt.Sprintf("relations should be created for both models %s and %s", related.Name, model.Name)
+		if property.RelationName != "" {
+			text = fmt.Sprintf("relation %s should be created for both models %s and %s", property.RelationName, related.Name, model.Name)
+		}
+		return &validation_error.ValidationError{
+			Position: validation_error.RelationValidationError,
+			Text:     text,
+		}
+	}
+	if len(pairs) > 1 {
+		return &validation_error.ValidationError{
+			Position: validation_error.RelationValidationError,
+			Text:     fmt.Sprintf("property %s in model %s matches more than one relation of model %s, set the same relationName on both sides of each relation", property.Name, model.Name, related.Name),
+		}
+	}
+	if pairs[0].RelationField != "" && property.RelationField != "" {
+		return &validation_error.ValidationError{
+			Position: validation_error.RelationValidationError,
+			Text:     fmt.Sprintf("relationField of relation between %s.%s and %s.%s should be set only on one side", model.Name, property.Name, related.Name, pairs[0].Name),
+		}
+	}
+	if pairs[0].RelationField == "" && property.RelationField == "" {
+		return validateJoinTable(model, property, related, pairs[0], modelNames)
+	}
+	return nil
+}
+
+// validateJoinTable checks implicit many-to-many relation, other relations should have relationField on one side
+func validateJoinTable(model schema_model.Model, property schema_model.Property, related schema_model.Model, pair schema_model.Property, modelNames []string) *validation_error.ValidationError {
+	joinTable, isJoinTable := model.GetJoinTable(property, related)
+	if !isJoinTable {
+		return &validation_error.ValidationError{
+			Position: validation_error.RelationValidationError,
+			Text:     fmt.Sprintf("relation between %s.%s and %s.%s should have relationField and referenceField on one side, only many-to-many relations of two lists can omit them", model.Name, property.Name, related.Name, pair.Name),
+		}
+	}
+	for _, side := range []schema_model.Model{model, related} {
+		if len(side.GetPrimaryKey()) != 1 {
+			return &validation_error.ValidationError{
+				Position: validation_error.RelationValidationError,
+				Text:     fmt.Sprintf("many-to-many relation %s requires model %s with single id property", joinTable.Name, side.Name),
+			}
+		}
+	}
+	if strings.HasPrefix(joinTable.Name, "_gorel") || slices.Contains(modelNames, joinTable.Name) {
+		return &validation_error.ValidationError{
+			Position: validation_error.RelationValidationError,
+			Text:     fmt.Sprintf("join table name %s of many-to-many relation is reserved, set another relationName", joinTable.Name),
+		}
+	}
+	return nil
+}
+
+// validateReferentialAction checks that the action can be applied to the relation field of the property
+func validateReferentialAction(model schema_model.Model, property schema_model.Property, option string, action schema_model.ReferentialAction, provider schema_model.Provider) *validation_error.ValidationError {
+	if action == "" {
+		return nil
+	}
+	if !slices.Contains(schema_model.ReferentialActions, action) {
+		return &validation_error.ValidationError{
+			Position: validation_error.RelationValidationError,
+			Text:     fmt.Sprintf("unknown %s action %s of property %s in model %s, use one of: cascade, restrict, setNull, setDefault, noAction", option, action, property.Name, model.Name),
+		}
+	}
+	if property.RelationField == "" || property.ReferenceField == "" {
+		return &validation_error.ValidationError{
+			Position: validation_error.RelationValidationError,
+			Text:     fmt.Sprintf("%s of property %s in model %s can be set only on the relation property with relationField and referenceField", option, property.Name, model.Name),
+		}
+	}
+
+	relationFieldIndex := slices.IndexFunc(model.Properties, func(other schema_model.Property) bool { return other.Name == property.RelationField })
+	if relationFieldIndex == -1 {
+		return nil
+	}
+	relationField := model.Properties[relationFieldIndex]
+
+	switch action {
+	case schema_model.SetNull:
+		if !strings.HasSuffix(relationField.Type, "?") {
+			return &validation_error.ValidationError{
+				Position: validation_error.RelationValidationError,
+				Text:     fmt.Sprintf("%s action setNull of property %s in model %s requires nullable relation field %s", option, property.Name, model.Name, relationField.Name),
+			}
+		}
+	case schema_model.SetDefault:
+		if provider == schema_model.MySQL {
+			return &validation_error.ValidationError{
+				Position: validation_error.RelationValidationError,
+				Text:     fmt.Sprintf("%s action setDefault of property %s in model %s is not supported by mysql", option, property.Name, model.Name),
+			}
+		}
+		if relationField.Default == "" {
+			return &validation_error.ValidationError{
+				Position: validation_error.RelationValidationError,
+				Text:     fmt.Sprintf("%s action setDefault of property %s in model %s requires default value of relation field %s", option, property.Name, model.Name, relationField.Name),
+			}
+		}
+	}
+	return nil
+}
+
+func validateGenerator(schema schema_model.GoRelSchema) *validation_error.ValidationError {
+	if schema.Generator.NullableStrategy != "" && !slices.Contains(schema_model.NullableStrategies, schema.Generator.NullableStrategy) {
+		return &validation_error.ValidationError{
+			Position: validation_error.GeneratorValidationError,
+			Text:     fmt.Sprintf("unknown nullable strategy %s, use one of: %s", schema.Generator.NullableStrategy, joinNullableStrategies()),
+		}
+	}
+
+	var tagNames []string
+	for _, tag := range schema.Generator.Tags {
+		if !slices.Contains(schema_model.TagNames, tag.Name) {
+			return &validation_error.ValidationError{
+				Position: validation_error.GeneratorValidationError,
+				Text:     fmt.Sprintf("unknown tag %s, use one of: %s", tag.Name, strings.Join(schema_model.TagNames, ", ")),
+			}
+		}
+		if slices.Contains(tagNames, tag.Name) {
+			return &validation_error.ValidationError{
+				Position: validation_error.GeneratorValidationError,
+				Text:     fmt.Sprintf("tag %s is defined more than once", tag.Name),
+			}
+		}
+		tagNames = append(tagNames, tag.Name)
+
+		if tag.Naming != "" && !slices.Contains(schema_model.TagNamings, tag.Naming) {
+			return &validation_error.ValidationError{
+				Position: validation_error.GeneratorValidationError,
+				Text:     fmt.Sprintf("unknown naming %s of tag %s, use one of: camel, snake, original", tag.Naming, tag.Name),
+			}
+		}
+		if tag.OmitEmpty != "" && !slices.Contains(schema_model.OmitEmptyRules, tag.OmitEmpty) {
+			return &validation_error.ValidationError{
+				Position: validation_error.GeneratorValidationError,
+				Text:     fmt.Sprintf("unknown omitEmpty rule %s of tag %s, use one of: never, nullable, always", tag.OmitEmpty, tag.Name),
+			}
+		}
+	}
+
+	for _, template := range schema.Generator.Templates {
+		if template.Path == "" || template.Output == "" {
+			return &validation_error.ValidationError{
+				Position: validation_error.GeneratorValidationError,
+				Text:     fmt.Sprintf("template %s should have path and output", template.Path),
+			}
+		}
+		if template.Scope != "" && !slices.Contains(schema_model.TemplateScopes, template.Scope) {
+			return &validation_error.ValidationError{
+				Position: validation_error.GeneratorValidationError,
+				Text:     fmt.Sprintf("unknown scope %s of template %s, use one of: schema, model, enum", template.Scope, template.Path),
 			}
 		}
 	}
 	return nil
 }
 
+func joinNullableStrategies() string {
+	var names []string
+	for _, strategy := range schema_model.NullableStrategies {
+		names = append(names, string(strategy))
+	}
+	return strings.Join(names, ", ")
+}
+
 func ValidateSchema(schema *schema_model.GoRelSchema) (enumNames []string, modelNames []string, err error) {
 	enumNames, modelNames = schema_parser.IndexSchema(*schema)
 	if err := validateEnums(*schema); err != nil {
@@ -421,9 +754,12 @@ func ValidateSchema(schema *schema_model.GoRelSchema) (enumNames []string, model
 	if err := validateModels(*schema, enumNames, modelNames); err != nil {
 		return nil, nil, errors.New(fmt.Sprintf("error while validating models:\n%s", err))
 	}
-	if err := validateRelations(*schema); err != nil {
+	if err := validateRelations(*schema, modelNames); err != nil {
 		return nil, nil, errors.New(fmt.Sprintf("error while validating relations:\n%s", err))
 	}
+	if err := validateGenerator(*schema); err != nil {
+		return nil, nil, errors.New(fmt.Sprintf("error while validating generator:\n%s", err))
+	}
 
 	return enumNames, modelNames, nil
 }